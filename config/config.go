@@ -26,6 +26,11 @@ type DeviceConfig struct {
 
 	GroupID int `yaml:"groupID"`
 
+	// Labels are added to the device's dial-out data and Identities are
+	// the client certificate names (CN or SAN) which identify the device.
+	Labels     map[string]string
+	Identities []string
+
 	DeviceOptions `yaml:",inline"`
 }
 
@@ -38,11 +43,14 @@ type Sensor struct {
 	Origin            string
 	Path              string
 	Mode              string
-	SampleInterval    int  `yaml:"sampleInterval"`
-	HeartbeatInterval int  `yaml:"heartbeatInterval"`
-	SuppressRedundant bool `yaml:"suppressRedundant"`
+	SampleInterval    int    `yaml:"sampleInterval"`
+	HeartbeatInterval int    `yaml:"heartbeatInterval"`
+	SuppressRedundant bool   `yaml:"suppressRedundant"`
+	ListMode          string `yaml:"listMode"`
+	PollInterval      int    `yaml:"pollInterval"`
 
 	Subscription string
+	Encoding     string
 }
 
 // Device represents device configuration with sensors
@@ -66,6 +74,16 @@ type Database struct {
 	Config  interface{}
 }
 
+// Processor represents a data processor which runs before the routing,
+// it applies to all data once the outputs and the sensors are empty.
+type Processor struct {
+	Name    string
+	Service string
+	Outputs []string
+	Sensors []string
+	Config  interface{}
+}
+
 // Global represents global configuration
 type Global struct {
 	Discovery        Discovery
@@ -78,6 +96,13 @@ type Global struct {
 	Version          string
 	Logger           map[string]interface{}
 	Dialout          Dialout
+	MDT              MDT `yaml:"mdt"`
+	Watchdog         Watchdog
+
+	MaxConcurrentDials int      `yaml:"maxConcurrentDials"`
+	Counter64Leaves    []string `yaml:"counter64Leaves"`
+	Dialers            map[string]Dialer
+	Processors         []Processor
 }
 
 // TLSConfig represents TLS client configuration
@@ -116,6 +141,37 @@ type DeviceOptions struct {
 	Username  string
 	Password  string
 	Timeout   int
+	Backoff   Backoff
+
+	KeepaliveTime     int   `yaml:"keepaliveTime"`
+	KeepaliveTimeout  int   `yaml:"keepaliveTimeout"`
+	MaxRecvMsgSize    int   `yaml:"maxRecvMsgSize"`
+	InitialWindowSize int32 `yaml:"initialWindowSize"`
+	Gzip              bool
+
+	Dialer string
+}
+
+// Dialer represents a proxy or jump host which the devices are dialed through
+type Dialer struct {
+	Type     string
+	Addr     string
+	Username string
+	Password string
+	Timeout  int
+
+	KeyFile               string `yaml:"keyFile"`
+	KnownHostsFile        string `yaml:"knownHostsFile"`
+	InsecureIgnoreHostKey bool   `yaml:"insecureIgnoreHostKey"`
+}
+
+// Backoff represents the reconnect backoff policy
+type Backoff struct {
+	Initial     int
+	Max         int
+	Multiplier  float64
+	Jitter      *float64
+	ResetWindow int `yaml:"resetWindow"`
 }
 
 // Dialout represents dialout service
@@ -125,10 +181,26 @@ type Dialout struct {
 	Services      map[string]DialoutService
 }
 
+// MDT represents Cisco MDT configuration
+type MDT struct {
+	ProtoPaths []string `yaml:"protoPaths"`
+}
+
+// Watchdog represents the stale-stream watchdog configuration
+type Watchdog struct {
+	Enabled         bool
+	MissedIntervals int `yaml:"missedIntervals"`
+}
+
 // DialoutService represent specific dialout telemetry
 type DialoutService struct {
-	Addr    string
-	Workers int
+	Name       string `yaml:"-"`
+	Addr       string
+	Workers    int
+	Transport  string
+	MaxMsgSize int       `yaml:"maxMsgSize"`
+	TLSConfig  TLSConfig `yaml:"tlsConfig"`
+	ClientAuth string    `yaml:"clientAuth"`
 }
 
 // DeviceTemplate represents device configuration structure
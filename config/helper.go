@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 	"strconv"
+	"strings"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -93,18 +94,29 @@ func DeviceValidation(device Device) error {
 // SensorValidation validates configured sensor.
 func SensorValidation(sensor Sensor) error {
 	availSensors := map[string]bool{
-		"arista.gnmi":       true,
-		"juniper.gnmi":      true,
-		"cisco.gnmi":        true,
-		"cisco.mdt":         true,
-		"cisco.mdt.dialout": true,
-		"juniper.jti":       true,
+		"arista.gnmi":            true,
+		"juniper.gnmi":           true,
+		"cisco.gnmi":             true,
+		"cisco.mdt":              true,
+		"cisco.mdt.dialout":      true,
+		"juniper.jti":            true,
+		"juniper.native.dialout": true,
+		"nokia.gnmi":             true,
+		"gnmi":                   true,
+		"gnmi.dialout":           true,
+		"gnmi.get":               true,
 	}
 
 	if _, ok := availSensors[sensor.Service]; !ok {
 		return fmt.Errorf("sensor:%s not available", sensor.Service)
 	}
 
+	switch strings.ToLower(sensor.ListMode) {
+	case "", "stream", "poll", "once":
+	default:
+		return fmt.Errorf("sensor:%s invalid list mode %s", sensor.Service, sensor.ListMode)
+	}
+
 	return nil
 }
 
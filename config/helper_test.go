@@ -61,6 +61,12 @@ func TestSensorValidation(t *testing.T) {
 	sensor = Sensor{Service: "noname.gnmi"}
 	err = SensorValidation(sensor)
 	assert.Error(t, err)
+	sensor = Sensor{Service: "gnmi", ListMode: "POLL"}
+	err = SensorValidation(sensor)
+	assert.NoError(t, err)
+	sensor = Sensor{Service: "gnmi", ListMode: "sample"}
+	err = SensorValidation(sensor)
+	assert.Error(t, err)
 }
 
 func TestSensorSanitization(t *testing.T) {
@@ -85,6 +85,11 @@ L:
 				break L
 			}
 
+			// the delete and sync events are not time series
+			if _, ok := v.DS["operation"]; ok {
+				continue
+			}
+
 			line, err := getLineProtocol(buf, v)
 			if err != nil {
 				i.logger.Error("influxdb", zap.Error(err), zap.String("output", v.Output))
@@ -5,6 +5,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"os"
 	"os/signal"
@@ -21,17 +22,23 @@ import (
 	"github.com/yahoo/panoptes-stream/discovery/etcd"
 	"github.com/yahoo/panoptes-stream/discovery/k8s"
 	"github.com/yahoo/panoptes-stream/discovery/pseudo"
+	"github.com/yahoo/panoptes-stream/processor"
 	"github.com/yahoo/panoptes-stream/producer"
 	"github.com/yahoo/panoptes-stream/register"
+	"github.com/yahoo/panoptes-stream/secret"
 	"github.com/yahoo/panoptes-stream/status"
 	"github.com/yahoo/panoptes-stream/telemetry"
+	"github.com/yahoo/panoptes-stream/telemetry/cisco/mdt"
 	"github.com/yahoo/panoptes-stream/telemetry/dialout"
+	"github.com/yahoo/panoptes-stream/telemetry/generic/gnmi"
 )
 
 var (
 	producerRegistrar  *producer.Registrar
 	databaseRegistrar  *database.Registrar
 	telemetryRegistrar *telemetry.Registrar
+	dialoutRegistrar   *dialout.Registrar
+	processorRegistrar *processor.Registrar
 )
 
 func main() {
@@ -54,6 +61,7 @@ func main() {
 	defer logger.Sync()
 
 	outChan := make(telemetry.ExtDSChan, cfg.Global().BufferSize)
+	demuxChan := make(telemetry.ExtDSChan, cfg.Global().BufferSize)
 
 	// discovery
 	discovery, err = discoveryRegister(cfg)
@@ -73,14 +81,34 @@ func main() {
 	databaseRegistrar = database.NewRegistrar(logger)
 	register.Database(databaseRegistrar)
 
+	// cisco mdt compact gpb schemas
+	if err := mdt.LoadProtos(cfg.Global().MDT.ProtoPaths); err != nil {
+		logger.Error("cisco.mdt", zap.String("event", "load protos"), zap.Error(err))
+	}
+
+	// gnmi json 64-bit counter leaves
+	gnmi.SetCounter64Leaves(cfg.Global().Counter64Leaves)
+
+	// processor
+	processorRegistrar = processor.NewRegistrar(logger)
+	register.Processor(processorRegistrar)
+
 	// telemetry
 	telemetryRegistrar = telemetry.NewRegistrar(logger)
 	register.Telemetry(telemetryRegistrar)
 
+	// dial-out telemetry
+	dialoutRegistrar = dialout.NewRegistrar(logger)
+	register.Dialout(dialoutRegistrar)
+
 	// start demux
-	d := demux.New(ctx, cfg, producerRegistrar, databaseRegistrar, outChan)
+	d := demux.New(ctx, cfg, producerRegistrar, databaseRegistrar, demuxChan)
 	d.Start()
 
+	// start processors
+	p := processor.New(ctx, cfg, processorRegistrar, outChan, demuxChan)
+	p.Start()
+
 	// start telemetry
 	t := telemetry.New(ctx, cfg, telemetryRegistrar, outChan)
 	if !cfg.Global().Shards.Enabled {
@@ -88,16 +116,20 @@ func main() {
 	}
 
 	// start telemetry dialout
-	i := dialout.New(ctx, cfg, outChan)
+	i := dialout.New(ctx, cfg, dialoutRegistrar, outChan)
 	i.Start()
 
 	// status
 	if !cfg.Global().Status.Disabled {
-		s := status.New(cfg)
+		s := status.New(cfg, func(tlsConf *config.TLSConfig) (*tls.Config, error) {
+			// the status server runs until the process exits
+			tlsConfig, _, err := secret.GetTLSServerReloadConfig(ctx, "status", tlsConf, tls.NoClientCert)
+			return tlsConfig, err
+		})
 		s.Start()
 	}
 
-	go updateLoop(cfg, t, d, i, updateRequest)
+	go updateLoop(cfg, t, d, i, p, updateRequest)
 
 	if cfg.Global().Shards.Enabled && discovery != nil {
 		shards := NewShards(cfg, t, discovery, updateRequest)
@@ -107,7 +139,7 @@ func main() {
 	<-signalCh
 }
 
-func updateLoop(cfg config.Config, t *telemetry.Telemetry, d *demux.Demux, i *dialout.Dialout, updateRequest chan struct{}) {
+func updateLoop(cfg config.Config, t *telemetry.Telemetry, d *demux.Demux, i *dialout.Dialout, p *processor.Pipeline, updateRequest chan struct{}) {
 	var informed bool
 
 	for {
@@ -131,6 +163,7 @@ func updateLoop(cfg config.Config, t *telemetry.Telemetry, d *demux.Demux, i *di
 		}
 
 		d.Update()
+		p.Update()
 		t.Update()
 		i.Update()
 	}
@@ -0,0 +1,245 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package aggregate
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/processor"
+	"github.com/yahoo/panoptes-stream/status"
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+var functions = map[string]func(w *window) interface{}{
+	"min":  func(w *window) interface{} { return w.min },
+	"max":  func(w *window) interface{} { return w.max },
+	"avg":  func(w *window) interface{} { return w.sum / float64(w.count) },
+	"last": func(w *window) interface{} { return w.ds["value"] },
+}
+
+var metrics = map[string]status.Metrics{
+	"windowsTotal": status.NewCounter("processor_aggregate_windows_total", ""),
+	"lateTotal":    status.NewCounter("processor_aggregate_late_total", ""),
+}
+
+type aggregateConfig struct {
+	Window    int
+	Functions []string
+	Keys      []string
+	Output    string
+}
+
+// Aggregate represents windowed aggregation processor, it aggregates
+// the data per series over the tumbling windows.
+type Aggregate struct {
+	cfg    *aggregateConfig
+	window time.Duration
+	keys   map[string]bool
+	series map[string]*window
+
+	now func() time.Time
+}
+
+type window struct {
+	start  time.Time
+	ds     telemetry.DataStore
+	output string
+	seen   time.Time
+
+	count         int
+	min, max, sum float64
+}
+
+// New constructs a new aggregate processor.
+func New(conf config.Processor, _ *zap.Logger) (processor.Processor, error) {
+	cfg, err := getConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Aggregate{
+		cfg:    cfg,
+		window: time.Duration(cfg.Window) * time.Second,
+		keys:   make(map[string]bool),
+		series: make(map[string]*window),
+		now:    time.Now,
+	}
+
+	for _, key := range cfg.Keys {
+		a.keys[key] = true
+	}
+
+	return a, nil
+}
+
+// Process adds the numeric data to its series window and emits the previous
+// window once the data belongs to the next window, the rest of the data passes
+// through. the raw data is replaced by the aggregated data (downsampling) once
+// the output is empty, otherwise it passes through to its own output.
+func (a *Aggregate) Process(extDS telemetry.ExtDataStore) []telemetry.ExtDataStore {
+	value, ok := a.match(extDS.DS)
+	if !ok {
+		return []telemetry.ExtDataStore{extDS}
+	}
+
+	ts, ok := processor.GetTime(extDS.DS)
+	if !ok {
+		return []telemetry.ExtDataStore{extDS}
+	}
+
+	var result []telemetry.ExtDataStore
+
+	if a.cfg.Output != "" {
+		result = append(result, extDS)
+	}
+
+	id := processor.GetSeriesKey(extDS.DS)
+	start := ts.Truncate(a.window)
+
+	w, ok := a.series[id]
+	if ok && start.Before(w.start) {
+		metrics["lateTotal"].Inc()
+		return result
+	}
+
+	if ok && start.After(w.start) {
+		result = append(result, a.emit(w)...)
+		ok = false
+	}
+
+	if !ok {
+		w = &window{start: start, min: value, max: value}
+		a.series[id] = w
+	}
+
+	w.ds = extDS.DS
+	w.output = extDS.Output
+	w.seen = a.now()
+	w.count++
+	w.sum += value
+	w.min = math.Min(w.min, value)
+	w.max = math.Max(w.max, value)
+
+	return result
+}
+
+// Flush emits the windows of the series which they haven't been seen
+// during a window and removes them.
+func (a *Aggregate) Flush(now time.Time) []telemetry.ExtDataStore {
+	var result []telemetry.ExtDataStore
+
+	for id, w := range a.series {
+		if now.Sub(w.seen) > a.window {
+			result = append(result, a.emit(w)...)
+			delete(a.series, id)
+		}
+	}
+
+	return result
+}
+
+// emit returns the aggregated data per function, the function name is
+// added to the key as suffix once there is more than one function.
+func (a *Aggregate) emit(w *window) []telemetry.ExtDataStore {
+	var result []telemetry.ExtDataStore
+
+	output := w.output
+	if a.cfg.Output != "" {
+		output = a.cfg.Output
+	}
+
+	for _, name := range a.cfg.Functions {
+		ds := make(telemetry.DataStore, len(w.ds))
+		for k, v := range w.ds {
+			ds[k] = v
+		}
+
+		ds["value"] = functions[name](w)
+		ds["timestamp"] = processor.FormatTime(w.ds["timestamp"], w.start)
+
+		if len(a.cfg.Functions) > 1 {
+			ds["key"] = w.ds["key"].(string) + "_" + name
+		}
+
+		result = append(result, telemetry.ExtDataStore{DS: ds, Output: output})
+	}
+
+	metrics["windowsTotal"].Inc()
+
+	return result
+}
+
+// match returns the numeric value if the data belongs to the keys.
+func (a *Aggregate) match(ds telemetry.DataStore) (float64, bool) {
+	key, ok := ds["key"].(string)
+	if !ok || processor.IsOperation(ds) {
+		return 0, false
+	}
+
+	if !processor.MatchKey(a.keys, key) {
+		return 0, false
+	}
+
+	return getValue(ds["value"])
+}
+
+func getValue(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	}
+
+	return 0, false
+}
+
+func getConfig(conf config.Processor) (*aggregateConfig, error) {
+	cfg := new(aggregateConfig)
+	b, err := json.Marshal(conf.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Functions) < 1 {
+		cfg.Functions = []string{"avg"}
+	}
+
+	for _, name := range cfg.Functions {
+		if _, ok := functions[name]; !ok {
+			return nil, errors.New("unknown function: " + name)
+		}
+	}
+
+	config.SetDefault(&cfg.Window, 60)
+
+	return cfg, nil
+}
+
+// Register registers aggregate as a processor at processor registrar
+func Register(processorRegistrar *processor.Registrar) {
+	status.Register(status.Labels{}, metrics)
+	processorRegistrar.Register("aggregate", "-", New)
+}
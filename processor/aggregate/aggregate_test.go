@@ -0,0 +1,110 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package aggregate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+func newExtDS(key string, value interface{}, ts uint64) telemetry.ExtDataStore {
+	return telemetry.ExtDataStore{
+		DS: telemetry.DataStore{
+			"system_id": "core1.lax",
+			"prefix":    "/interfaces/interface/state/counters/",
+			"labels":    map[string]string{"name": "Ethernet1"},
+			"timestamp": ts,
+			"key":       key,
+			"value":     value,
+		},
+		Output: "kafka1::interfaces",
+	}
+}
+
+func newAggregate(t *testing.T, cfg map[string]interface{}) *Aggregate {
+	p, err := New(config.Processor{Name: "aggregate1", Service: "aggregate", Config: cfg}, nil)
+	assert.NoError(t, err)
+
+	return p.(*Aggregate)
+}
+
+func TestAggregate(t *testing.T) {
+	a := newAggregate(t, map[string]interface{}{"functions": []string{"min", "max", "avg", "last"}})
+
+	// the raw data is replaced by the aggregated data once the output is empty
+	for i, value := range []int64{10, 40, 20, 30, 50, 60} {
+		result := a.Process(newExtDS("in-pkts", value, 1599982200000+uint64(i)*10000))
+		assert.Len(t, result, 0)
+	}
+
+	// the next window
+	result := a.Process(newExtDS("in-pkts", int64(70), 1599982260000))
+	assert.Len(t, result, 4)
+
+	expected := map[string]interface{}{
+		"in-pkts_min":  float64(10),
+		"in-pkts_max":  float64(60),
+		"in-pkts_avg":  float64(35),
+		"in-pkts_last": int64(60),
+	}
+
+	for _, extDS := range result {
+		assert.Equal(t, expected[extDS.DS["key"].(string)], extDS.DS["value"], extDS.DS["key"])
+		assert.Equal(t, uint64(1599982200000), extDS.DS["timestamp"])
+		assert.Equal(t, "kafka1::interfaces", extDS.Output)
+	}
+
+	// the late data is dropped
+	result = a.Process(newExtDS("in-pkts", int64(70), 1599982250000))
+	assert.Len(t, result, 0)
+
+	// the non-numeric and the data without timestamp pass through
+	result = a.Process(newExtDS("oper-status", "UP", 1599982260000))
+	assert.Len(t, result, 1)
+
+	result = a.Process(newExtDS("in-pkts", int64(70), 0))
+	assert.Len(t, result, 1)
+	assert.Equal(t, int64(70), result[0].DS["value"])
+}
+
+func TestAggregateOutput(t *testing.T) {
+	a := newAggregate(t, map[string]interface{}{"window": 30, "output": "influxdb1::longterm", "keys": []string{"in-pkts"}})
+
+	now := time.Now()
+	a.now = func() time.Time { return now }
+
+	// the raw data passes through to its output
+	result := a.Process(newExtDS("in-pkts", uint64(10), 1599982200000))
+	assert.Len(t, result, 1)
+	assert.Equal(t, "kafka1::interfaces", result[0].Output)
+
+	a.Process(newExtDS("in-pkts", uint64(20), 1599982210000))
+
+	result = a.Process(newExtDS("out-pkts", uint64(20), 1599982210000))
+	assert.Len(t, result, 1)
+
+	// the series is flushed once it's not seen during a window
+	assert.Len(t, a.Flush(now.Add(20*time.Second)), 0)
+
+	result = a.Flush(now.Add(31 * time.Second))
+	assert.Len(t, result, 1)
+	assert.Equal(t, "in-pkts", result[0].DS["key"])
+	assert.Equal(t, float64(15), result[0].DS["value"])
+	assert.Equal(t, "influxdb1::longterm", result[0].Output)
+	assert.Len(t, a.series, 0)
+}
+
+func TestGetConfig(t *testing.T) {
+	_, err := New(config.Processor{Config: map[string]interface{}{"functions": []string{"median"}}}, nil)
+	assert.Error(t, err)
+
+	a := newAggregate(t, nil)
+	assert.Equal(t, []string{"avg"}, a.cfg.Functions)
+	assert.Equal(t, time.Minute, a.window)
+}
@@ -0,0 +1,125 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package processor
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+// GetSeriesKey returns the series identity of the data
+// which is the system_id, prefix, labels and key.
+func GetSeriesKey(ds telemetry.DataStore) string {
+	var b strings.Builder
+
+	systemID, _ := ds["system_id"].(string)
+	prefix, _ := ds["prefix"].(string)
+	key, _ := ds["key"].(string)
+	labels, _ := ds["labels"].(map[string]string)
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b.WriteString(systemID)
+	b.WriteByte('|')
+	b.WriteString(prefix)
+	b.WriteByte('|')
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(labels[name])
+		b.WriteByte(',')
+	}
+	b.WriteByte('|')
+	b.WriteString(key)
+
+	return b.String()
+}
+
+// GetTime returns the data timestamp, the telemetries use different
+// precisions (second to nanosecond) and it's detected by the magnitude.
+func GetTime(ds telemetry.DataStore) (time.Time, bool) {
+	ts, ok := getTimestamp(ds["timestamp"])
+	if !ok || ts <= 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, ts*int64(getPrecision(ts))), true
+}
+
+// FormatTime returns the time in the same type and precision as the given timestamp.
+func FormatTime(timestamp interface{}, t time.Time) interface{} {
+	ts, ok := getTimestamp(timestamp)
+	if !ok || ts <= 0 {
+		return t.UnixNano()
+	}
+
+	ts = t.UnixNano() / int64(getPrecision(ts))
+
+	switch timestamp.(type) {
+	case uint64:
+		return uint64(ts)
+	case int:
+		return int(ts)
+	case float64:
+		return float64(ts)
+	}
+
+	return ts
+}
+
+func getTimestamp(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return v, true
+	case uint64:
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	}
+
+	return 0, false
+}
+
+func getPrecision(ts int64) time.Duration {
+	switch {
+	case ts < 1e11:
+		return time.Second
+	case ts < 1e14:
+		return time.Millisecond
+	case ts < 1e17:
+		return time.Microsecond
+	}
+
+	return time.Nanosecond
+}
+
+// MatchKey returns true if the key or its last element belongs to
+// the keys e.g. counters/in-octets matches in-octets, it matches all
+// keys once the keys are empty.
+func MatchKey(keys map[string]bool, key string) bool {
+	if len(keys) < 1 || keys[key] {
+		return true
+	}
+
+	if i := strings.LastIndex(key, "/"); i > -1 {
+		return keys[key[i+1:]]
+	}
+
+	return false
+}
+
+// IsOperation returns true if the data is an operation e.g. delete or sync.
+func IsOperation(ds telemetry.DataStore) bool {
+	_, ok := ds["operation"]
+	return ok
+}
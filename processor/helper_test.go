@@ -0,0 +1,72 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+func TestGetSeriesKey(t *testing.T) {
+	ds := telemetry.DataStore{
+		"system_id": "core1.lax",
+		"prefix":    "/interfaces/interface/state/counters/",
+		"labels":    map[string]string{"name": "Ethernet1", "subscriptionId": "Sub1"},
+		"key":       "in-octets",
+	}
+
+	key := GetSeriesKey(ds)
+	assert.Equal(t, "core1.lax|/interfaces/interface/state/counters/|name=Ethernet1,subscriptionId=Sub1,|in-octets", key)
+
+	ds["labels"] = map[string]string{"subscriptionId": "Sub1", "name": "Ethernet1"}
+	assert.Equal(t, key, GetSeriesKey(ds))
+
+	ds["labels"] = map[string]string{"name": "Ethernet2", "subscriptionId": "Sub1"}
+	assert.NotEqual(t, key, GetSeriesKey(ds))
+}
+
+func TestGetTime(t *testing.T) {
+	expected := time.Unix(1599982184, 0)
+
+	for _, ts := range []interface{}{
+		int64(1599982184),
+		uint64(1599982184000),
+		int64(1599982184000000),
+		int64(1599982184000000000),
+		float64(1599982184000000),
+	} {
+		v, ok := GetTime(telemetry.DataStore{"timestamp": ts})
+		assert.True(t, ok)
+		assert.True(t, expected.Equal(v), ts)
+	}
+
+	_, ok := GetTime(telemetry.DataStore{"timestamp": "1599982184"})
+	assert.False(t, ok)
+
+	_, ok = GetTime(telemetry.DataStore{})
+	assert.False(t, ok)
+}
+
+func TestFormatTime(t *testing.T) {
+	ts := time.Unix(1599982200, 0)
+
+	assert.Equal(t, uint64(1599982200000), FormatTime(uint64(1599982184000), ts))
+	assert.Equal(t, int64(1599982200000000000), FormatTime(int64(1599982184000000000), ts))
+	assert.Equal(t, float64(1599982200000000), FormatTime(float64(1599982184000000), ts))
+	assert.Equal(t, int64(1599982200000000000), FormatTime(nil, ts))
+}
+
+func TestMatchKey(t *testing.T) {
+	assert.True(t, MatchKey(nil, "in-octets"))
+
+	keys := map[string]bool{"in-octets": true}
+	assert.True(t, MatchKey(keys, "in-octets"))
+	assert.True(t, MatchKey(keys, "counters/in-octets"))
+	assert.False(t, MatchKey(keys, "out-octets"))
+	assert.False(t, MatchKey(keys, "in-octets/out-octets"))
+}
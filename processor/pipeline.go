@@ -0,0 +1,247 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package processor
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/status"
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+// flushInterval is the interval which the flushers are called.
+const flushInterval = time.Second
+
+// Pipeline represents the ordered processors chain which runs on
+// the telemetries data before they're routed to the outputs.
+type Pipeline struct {
+	ctx     context.Context
+	cfg     config.Config
+	logger  *zap.Logger
+	pr      *Registrar
+	inChan  telemetry.ExtDSChan
+	outChan telemetry.ExtDSChan
+	metrics map[string]status.Metrics
+
+	chain []*stage
+	sync.RWMutex
+}
+
+// stage represents a processor in the chain with its scope.
+type stage struct {
+	conf      config.Processor
+	processor Processor
+	outputs   map[string]bool
+	sensors   []string
+}
+
+// New constructs a new processors pipeline.
+func New(ctx context.Context, cfg config.Config, pr *Registrar, inChan, outChan telemetry.ExtDSChan) *Pipeline {
+	var metrics = make(map[string]status.Metrics)
+
+	metrics["dataInTotal"] = status.NewCounter("processor_data_in_total", "")
+	metrics["dataOutTotal"] = status.NewCounter("processor_data_out_total", "")
+
+	status.Register(status.Labels{}, metrics)
+
+	return &Pipeline{
+		ctx:     ctx,
+		cfg:     cfg,
+		logger:  cfg.Logger(),
+		pr:      pr,
+		inChan:  inChan,
+		outChan: outChan,
+		metrics: metrics,
+	}
+}
+
+// Start builds the chain and starts processing.
+func (p *Pipeline) Start() {
+	p.Update()
+
+	go p.start()
+}
+
+// Update rebuilds the chain once the processors configuration changed,
+// the unchanged processors keep their instances and their states.
+func (p *Pipeline) Update() {
+	var (
+		chain   []*stage
+		names   = make(map[string]bool)
+		current = make(map[string]*stage)
+	)
+
+	p.RLock()
+	for _, s := range p.chain {
+		current[s.conf.Name] = s
+	}
+	p.RUnlock()
+
+	for _, conf := range p.cfg.Global().Processors {
+		if names[conf.Name] {
+			p.logger.Error("processor", zap.String("event", "duplicate"), zap.String("name", conf.Name))
+			continue
+		}
+		names[conf.Name] = true
+
+		if s, ok := current[conf.Name]; ok && reflect.DeepEqual(s.conf, conf) {
+			chain = append(chain, s)
+			continue
+		}
+
+		s, err := p.newStage(conf)
+		if err != nil {
+			p.logger.Error("processor", zap.String("event", "new"), zap.String("name", conf.Name), zap.Error(err))
+			continue
+		}
+
+		p.logger.Info("processor", zap.String("event", "start"), zap.String("name", conf.Name), zap.String("service", conf.Service))
+
+		chain = append(chain, s)
+	}
+
+	p.Lock()
+	p.chain = chain
+	p.Unlock()
+}
+
+func (p *Pipeline) newStage(conf config.Processor) (*stage, error) {
+	new, ok := p.pr.GetProcessorFactory(conf.Service)
+	if !ok {
+		return nil, errors.New("processor not exist")
+	}
+
+	processor, err := new(conf, p.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &stage{
+		conf:      conf,
+		processor: processor,
+		outputs:   make(map[string]bool),
+	}
+
+	for _, output := range conf.Outputs {
+		s.outputs[output] = true
+	}
+
+	for _, sensor := range conf.Sensors {
+		s.sensors = append(s.sensors, strings.TrimSuffix(sensor, "/"))
+	}
+
+	return s, nil
+}
+
+func (p *Pipeline) start() {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case extDS := <-p.inChan:
+			p.metrics["dataInTotal"].Inc()
+
+			chain := p.getChain()
+			if len(chain) < 1 {
+				p.send(extDS)
+				continue
+			}
+
+			for _, extDS := range process(chain, []telemetry.ExtDataStore{extDS}, 0) {
+				p.send(extDS)
+			}
+
+		case now := <-ticker.C:
+			chain := p.getChain()
+			for i, s := range chain {
+				f, ok := s.processor.(Flusher)
+				if !ok {
+					continue
+				}
+
+				for _, extDS := range process(chain, f.Flush(now), i+1) {
+					p.send(extDS)
+				}
+			}
+
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *Pipeline) send(extDS telemetry.ExtDataStore) {
+	select {
+	case p.outChan <- extDS:
+		p.metrics["dataOutTotal"].Inc()
+	case <-p.ctx.Done():
+	}
+}
+
+func (p *Pipeline) getChain() []*stage {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.chain
+}
+
+// process runs the chain from the given stage, the data which
+// doesn't belong to a processor's scope skips the processor.
+func process(chain []*stage, data []telemetry.ExtDataStore, from int) []telemetry.ExtDataStore {
+	for _, s := range chain[from:] {
+		if len(data) < 1 {
+			break
+		}
+
+		var result []telemetry.ExtDataStore
+		for _, extDS := range data {
+			if !s.match(extDS) {
+				result = append(result, extDS)
+				continue
+			}
+
+			result = append(result, s.processor.Process(extDS)...)
+		}
+
+		data = result
+	}
+
+	return data
+}
+
+// match returns true if the data belongs to the processor's outputs (output name
+// or output e.g. kafka1::interfaces) and sensors (path or subscription).
+func (s *stage) match(extDS telemetry.ExtDataStore) bool {
+	if len(s.outputs) > 0 {
+		name := strings.SplitN(extDS.Output, "::", 2)[0]
+		if !s.outputs[name] && !s.outputs[extDS.Output] {
+			return false
+		}
+	}
+
+	if len(s.sensors) < 1 {
+		return true
+	}
+
+	prefix, _ := extDS.DS["prefix"].(string)
+	prefix = strings.TrimSuffix(prefix, "/")
+	labels, _ := extDS.DS["labels"].(map[string]string)
+
+	for _, sensor := range s.sensors {
+		if prefix == sensor || strings.HasPrefix(prefix, sensor+"/") || labels["subscriptionId"] == sensor {
+			return true
+		}
+	}
+
+	return false
+}
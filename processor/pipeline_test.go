@@ -0,0 +1,173 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+// tag adds the processor name to the data's tags.
+type tag struct {
+	name string
+}
+
+func (t *tag) Process(extDS telemetry.ExtDataStore) []telemetry.ExtDataStore {
+	tags, _ := extDS.DS["tags"].(string)
+	extDS.DS["tags"] = tags + t.name + ";"
+
+	return []telemetry.ExtDataStore{extDS}
+}
+
+// hold holds the data and emits them at flush.
+type hold struct {
+	data []telemetry.ExtDataStore
+}
+
+func (h *hold) Process(extDS telemetry.ExtDataStore) []telemetry.ExtDataStore {
+	h.data = append(h.data, extDS)
+	return nil
+}
+
+func (h *hold) Flush(time.Time) []telemetry.ExtDataStore {
+	data := h.data
+	h.data = nil
+
+	return data
+}
+
+func testRegistrar(cfg config.Config) *Registrar {
+	pr := NewRegistrar(cfg.Logger())
+	pr.Register("test.tag", "-", func(conf config.Processor, _ *zap.Logger) (Processor, error) {
+		return &tag{name: conf.Name}, nil
+	})
+	pr.Register("test.hold", "-", func(config.Processor, *zap.Logger) (Processor, error) {
+		return &hold{}, nil
+	})
+
+	return pr
+}
+
+func newExtDS(output, prefix string) telemetry.ExtDataStore {
+	return telemetry.ExtDataStore{
+		DS: telemetry.DataStore{
+			"prefix": prefix,
+			"labels": map[string]string{"subscriptionId": "Sub1"},
+			"key":    "in-octets",
+		},
+		Output: output,
+	}
+}
+
+func TestRegistrar(t *testing.T) {
+	cfg := config.NewMockConfig()
+	pr := testRegistrar(cfg)
+
+	_, ok := pr.GetProcessorFactory("test.tag")
+	assert.True(t, ok)
+
+	_, ok = pr.GetProcessorFactory("test.notexist")
+	assert.False(t, ok)
+}
+
+func TestStageMatch(t *testing.T) {
+	s := &stage{outputs: map[string]bool{}}
+	assert.True(t, s.match(newExtDS("kafka1::core", "/interfaces/")))
+
+	s.outputs = map[string]bool{"kafka1": true, "influxdb1::bucket": true}
+	assert.True(t, s.match(newExtDS("kafka1::core", "/interfaces/")))
+	assert.True(t, s.match(newExtDS("influxdb1::bucket", "/interfaces/")))
+	assert.False(t, s.match(newExtDS("influxdb1::raw", "/interfaces/")))
+
+	s.sensors = []string{"/interfaces/interface", "Sub2"}
+	assert.True(t, s.match(newExtDS("kafka1::core", "/interfaces/interface/")))
+	assert.True(t, s.match(newExtDS("kafka1::core", "/interfaces/interface/state/counters")))
+	assert.False(t, s.match(newExtDS("kafka1::core", "/interfaces/interfaces")))
+	assert.False(t, s.match(newExtDS("kafka1::core", "/interfaces/")))
+
+	s.sensors = []string{"Sub1"}
+	assert.True(t, s.match(newExtDS("kafka1::core", "/interfaces/")))
+}
+
+func TestPipeline(t *testing.T) {
+	var (
+		cfg     = config.NewMockConfig()
+		inChan  = make(telemetry.ExtDSChan, 10)
+		outChan = make(telemetry.ExtDSChan, 10)
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg.MGlobal.Processors = []config.Processor{
+		{Name: "p1", Service: "test.tag"},
+		{Name: "p2", Service: "test.tag", Outputs: []string{"kafka1"}},
+		{Name: "p3", Service: "test.notexist"},
+		{Name: "p4", Service: "test.tag", Sensors: []string{"/interfaces"}},
+	}
+
+	p := New(ctx, cfg, testRegistrar(cfg), inChan, outChan)
+	p.Start()
+
+	assert.Len(t, p.getChain(), 3)
+
+	inChan <- newExtDS("kafka1::core", "/interfaces/")
+	inChan <- newExtDS("influxdb1::bucket", "/system/")
+
+	for _, tags := range []string{"p1;p2;p4;", "p1;"} {
+		select {
+		case extDS := <-outChan:
+			assert.Equal(t, tags, extDS.DS["tags"])
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	}
+
+	// the unchanged processors keep their instances
+	p1 := p.getChain()[0].processor
+	cfg.MGlobal.Processors = []config.Processor{
+		{Name: "p1", Service: "test.tag"},
+		{Name: "p5", Service: "test.hold", Outputs: []string{"influxdb1"}},
+		{Name: "p6", Service: "test.tag"},
+	}
+
+	p.Update()
+	assert.Len(t, p.getChain(), 3)
+	assert.True(t, p1 == p.getChain()[0].processor)
+
+	// the held data continues from the next processor once it's flushed
+	inChan <- newExtDS("influxdb1::bucket", "/system/")
+	inChan <- newExtDS("kafka1::core", "/interfaces/")
+
+	for _, tags := range []string{"p1;p6;", "p1;p6;"} {
+		select {
+		case extDS := <-outChan:
+			assert.Equal(t, tags, extDS.DS["tags"])
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout")
+		}
+	}
+
+	assert.Equal(t, uint64(4), p.metrics["dataInTotal"].Get())
+
+	// the data passes through once there is no processor
+	cfg.MGlobal.Processors = nil
+	p.Update()
+
+	inChan <- newExtDS("kafka1::core", "/interfaces/")
+
+	select {
+	case extDS := <-outChan:
+		assert.Nil(t, extDS.DS["tags"])
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+}
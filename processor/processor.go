@@ -0,0 +1,28 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package processor
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+// Factory is a function that returns a new instance of processor
+type Factory func(config.Processor, *zap.Logger) (Processor, error)
+
+// Processor represents a data processor, it returns the processed
+// data and the data is dropped once it returns nothing.
+type Processor interface {
+	Process(telemetry.ExtDataStore) []telemetry.ExtDataStore
+}
+
+// Flusher represents a processor which holds the data and
+// emits them later e.g. aggregation, it's called every second.
+type Flusher interface {
+	Flush(time.Time) []telemetry.ExtDataStore
+}
@@ -0,0 +1,264 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package rate
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/processor"
+	"github.com/yahoo/panoptes-stream/status"
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+const (
+	// ModeRate emits the per-second rate.
+	ModeRate = "rate"
+	// ModeDelta emits the difference between the samples.
+	ModeDelta = "delta"
+)
+
+var metrics = map[string]status.Metrics{
+	"resetsTotal":  status.NewCounter("processor_rate_resets_total", ""),
+	"wrapsTotal":   status.NewCounter("processor_rate_wraps_total", ""),
+	"expiredTotal": status.NewCounter("processor_rate_expired_total", ""),
+}
+
+type rateConfig struct {
+	Mode        string
+	Keys        []string
+	Replace     bool
+	Suffix      *string
+	CounterBits int
+	Expire      int
+}
+
+// Rate represents counter to rate processor, it keeps
+// the last sample per series and emits the rate or delta.
+type Rate struct {
+	cfg    *rateConfig
+	keys   map[string]bool
+	suffix string
+	max    uint64
+	expire time.Duration
+	series map[string]*sample
+
+	now func() time.Time
+}
+
+type sample struct {
+	value uint64
+	time  time.Time
+	seen  time.Time
+}
+
+// New constructs a new rate processor.
+func New(conf config.Processor, _ *zap.Logger) (processor.Processor, error) {
+	cfg, err := getConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Rate{
+		cfg:    cfg,
+		keys:   make(map[string]bool),
+		suffix: "_" + cfg.Mode,
+		max:    math.MaxUint64,
+		expire: time.Duration(cfg.Expire) * time.Second,
+		series: make(map[string]*sample),
+		now:    time.Now,
+	}
+
+	for _, key := range cfg.Keys {
+		r.keys[key] = true
+	}
+
+	if cfg.Suffix != nil {
+		r.suffix = *cfg.Suffix
+	}
+
+	if cfg.CounterBits == 32 {
+		r.max = math.MaxUint32
+	}
+
+	return r, nil
+}
+
+// Process emits the rate or delta of the counters once there is a previous
+// sample at the series, the rest of the data passes through.
+func (r *Rate) Process(extDS telemetry.ExtDataStore) []telemetry.ExtDataStore {
+	ds, ok := r.process(extDS.DS)
+	if !ok {
+		return []telemetry.ExtDataStore{extDS}
+	}
+
+	var result []telemetry.ExtDataStore
+
+	if !r.cfg.Replace {
+		result = append(result, extDS)
+	}
+
+	if ds != nil {
+		result = append(result, telemetry.ExtDataStore{DS: ds, Output: extDS.Output})
+	}
+
+	return result
+}
+
+// process returns the rate or delta data and true if the data is a counter.
+func (r *Rate) process(ds telemetry.DataStore) (telemetry.DataStore, bool) {
+	if !r.match(ds) {
+		return nil, false
+	}
+
+	id := processor.GetSeriesKey(ds)
+
+	if processor.IsOperation(ds) {
+		delete(r.series, id)
+		return nil, false
+	}
+
+	value, ok := getCounter(ds["value"])
+	if !ok {
+		return nil, false
+	}
+
+	ts, ok := processor.GetTime(ds)
+	if !ok {
+		return nil, true
+	}
+
+	last, ok := r.series[id]
+	if !ok {
+		r.series[id] = &sample{value: value, time: ts, seen: r.now()}
+		return nil, true
+	}
+
+	if !ts.After(last.time) {
+		return nil, true
+	}
+
+	delta, ok := r.delta(last.value, value)
+	elapsed := ts.Sub(last.time)
+	last.value, last.time, last.seen = value, ts, r.now()
+
+	if !ok {
+		return nil, true
+	}
+
+	result := make(telemetry.DataStore, len(ds))
+	for k, v := range ds {
+		result[k] = v
+	}
+
+	if r.cfg.Mode == ModeDelta {
+		result["value"] = delta
+	} else {
+		result["value"] = float64(delta) / elapsed.Seconds()
+	}
+
+	if !r.cfg.Replace {
+		result["key"] = ds["key"].(string) + r.suffix
+	}
+
+	return result, true
+}
+
+// Flush expires the series which they haven't been seen.
+func (r *Rate) Flush(now time.Time) []telemetry.ExtDataStore {
+	for id, s := range r.series {
+		if now.Sub(s.seen) > r.expire {
+			delete(r.series, id)
+			metrics["expiredTotal"].Inc()
+		}
+	}
+
+	return nil
+}
+
+// delta returns the counter difference, the counter which goes back
+// from the upper half of its range is wrapped otherwise it's reset
+// e.g. device reboot and it returns false.
+func (r *Rate) delta(last, value uint64) (uint64, bool) {
+	if value >= last {
+		return value - last, true
+	}
+
+	if last > r.max/2 && last <= r.max && value <= r.max {
+		metrics["wrapsTotal"].Inc()
+		return r.max - last + value + 1, true
+	}
+
+	metrics["resetsTotal"].Inc()
+
+	return 0, false
+}
+
+func (r *Rate) match(ds telemetry.DataStore) bool {
+	key, ok := ds["key"].(string)
+	if !ok {
+		return false
+	}
+
+	return processor.MatchKey(r.keys, key)
+}
+
+func getCounter(v interface{}) (uint64, bool) {
+	switch v := v.(type) {
+	case uint64:
+		return v, true
+	case uint32:
+		return uint64(v), true
+	case int64:
+		return uint64(v), v >= 0
+	case int32:
+		return uint64(v), v >= 0
+	case int:
+		return uint64(v), v >= 0
+	case float64:
+		return uint64(v), v >= 0 && v == math.Trunc(v) && v < math.MaxUint64
+	}
+
+	return 0, false
+}
+
+func getConfig(conf config.Processor) (*rateConfig, error) {
+	cfg := new(rateConfig)
+	b, err := json.Marshal(conf.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Mode == "" {
+		cfg.Mode = ModeRate
+	}
+
+	if cfg.Mode != ModeRate && cfg.Mode != ModeDelta {
+		return nil, errors.New("unknown mode: " + cfg.Mode)
+	}
+
+	if cfg.CounterBits != 0 && cfg.CounterBits != 32 && cfg.CounterBits != 64 {
+		return nil, errors.New("counterBits should be 32 or 64")
+	}
+
+	config.SetDefault(&cfg.Expire, 600)
+
+	return cfg, nil
+}
+
+// Register registers rate as a processor at processor registrar
+func Register(processorRegistrar *processor.Registrar) {
+	status.Register(status.Labels{}, metrics)
+	processorRegistrar.Register("rate", "-", New)
+}
@@ -0,0 +1,143 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package rate
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+func newExtDS(key string, value interface{}, ts int64) telemetry.ExtDataStore {
+	return telemetry.ExtDataStore{
+		DS: telemetry.DataStore{
+			"system_id": "core1.lax",
+			"prefix":    "/interfaces/interface/state/counters/",
+			"labels":    map[string]string{"name": "Ethernet1"},
+			"timestamp": ts,
+			"key":       key,
+			"value":     value,
+		},
+		Output: "kafka1::interfaces",
+	}
+}
+
+func newRate(t *testing.T, cfg map[string]interface{}) *Rate {
+	p, err := New(config.Processor{Name: "rate1", Service: "rate", Config: cfg}, nil)
+	assert.NoError(t, err)
+
+	return p.(*Rate)
+}
+
+func TestRate(t *testing.T) {
+	r := newRate(t, map[string]interface{}{"keys": []string{"in-octets"}})
+
+	// the first sample
+	result := r.Process(newExtDS("in-octets", uint64(1000), 1599982184000))
+	assert.Len(t, result, 1)
+	assert.Equal(t, uint64(1000), result[0].DS["value"])
+
+	result = r.Process(newExtDS("in-octets", uint64(3000), 1599982194000))
+	assert.Len(t, result, 2)
+	assert.Equal(t, "in-octets", result[0].DS["key"])
+	assert.Equal(t, uint64(3000), result[0].DS["value"])
+	assert.Equal(t, "in-octets_rate", result[1].DS["key"])
+	assert.Equal(t, float64(200), result[1].DS["value"])
+	assert.Equal(t, "kafka1::interfaces", result[1].Output)
+
+	// the old or duplicate sample
+	result = r.Process(newExtDS("in-octets", uint64(4000), 1599982194000))
+	assert.Len(t, result, 1)
+
+	// the other keys pass through
+	result = r.Process(newExtDS("oper-status", "UP", 1599982194000))
+	assert.Len(t, result, 1)
+	result = r.Process(newExtDS("out-octets", uint64(1000), 1599982194000))
+	assert.Len(t, result, 1)
+}
+
+func TestRateDeltaReplace(t *testing.T) {
+	r := newRate(t, map[string]interface{}{"mode": "delta", "replace": true})
+
+	result := r.Process(newExtDS("in-octets", int64(1000), 1599982184000000000))
+	assert.Len(t, result, 0)
+
+	result = r.Process(newExtDS("in-octets", int64(1500), 1599982194000000000))
+	assert.Len(t, result, 1)
+	assert.Equal(t, "in-octets", result[0].DS["key"])
+	assert.Equal(t, uint64(500), result[0].DS["value"])
+
+	// the non-counter data passes through
+	result = r.Process(newExtDS("oper-status", "UP", 1599982194000000000))
+	assert.Len(t, result, 1)
+	assert.Equal(t, "UP", result[0].DS["value"])
+}
+
+func TestRateWrapReset(t *testing.T) {
+	r := newRate(t, map[string]interface{}{"mode": "delta", "replace": true, "counterBits": 32})
+
+	r.Process(newExtDS("in-octets", uint64(math.MaxUint32-99), 1599982184))
+
+	// the counter wrapped
+	result := r.Process(newExtDS("in-octets", uint64(100), 1599982194))
+	assert.Len(t, result, 1)
+	assert.Equal(t, uint64(200), result[0].DS["value"])
+
+	// the device rebooted
+	result = r.Process(newExtDS("in-octets", uint64(10), 1599982204))
+	assert.Len(t, result, 0)
+	assert.Equal(t, uint64(1), metrics["wrapsTotal"].Get())
+	assert.Equal(t, uint64(1), metrics["resetsTotal"].Get())
+
+	result = r.Process(newExtDS("in-octets", uint64(50), 1599982214))
+	assert.Len(t, result, 1)
+	assert.Equal(t, uint64(40), result[0].DS["value"])
+
+	// the 64 bits counter doesn't wrap at 32 bits
+	r = newRate(t, map[string]interface{}{"mode": "delta", "replace": true})
+	r.Process(newExtDS("in-octets", uint64(math.MaxUint32-99), 1599982184))
+	result = r.Process(newExtDS("in-octets", uint64(100), 1599982194))
+	assert.Len(t, result, 0)
+}
+
+func TestRateExpire(t *testing.T) {
+	r := newRate(t, map[string]interface{}{"expire": 60})
+
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	r.Process(newExtDS("in-octets", uint64(1000), 1599982184))
+	assert.Len(t, r.series, 1)
+
+	r.Flush(now.Add(30 * time.Second))
+	assert.Len(t, r.series, 1)
+
+	r.Flush(now.Add(61 * time.Second))
+	assert.Len(t, r.series, 0)
+
+	// the delete operation removes the series
+	r.Process(newExtDS("in-octets", uint64(1000), 1599982184))
+	extDS := newExtDS("in-octets", nil, 1599982194)
+	extDS.DS["operation"] = telemetry.OperationDelete
+	result := r.Process(extDS)
+	assert.Len(t, result, 1)
+	assert.Len(t, r.series, 0)
+}
+
+func TestGetConfig(t *testing.T) {
+	_, err := New(config.Processor{Config: map[string]interface{}{"mode": "avg"}}, nil)
+	assert.Error(t, err)
+
+	_, err = New(config.Processor{Config: map[string]interface{}{"counterBits": 16}}, nil)
+	assert.Error(t, err)
+
+	r := newRate(t, map[string]interface{}{"suffix": "_bps"})
+	assert.Equal(t, "_bps", r.suffix)
+	assert.Equal(t, 600*time.Second, r.expire)
+}
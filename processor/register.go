@@ -0,0 +1,52 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package processor
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Registrar represents processor factory registration.
+type Registrar struct {
+	p      map[string]Factory
+	logger *zap.Logger
+	sync.RWMutex
+}
+
+// NewRegistrar creates new registrar.
+func NewRegistrar(logger *zap.Logger) *Registrar {
+	return &Registrar{
+		p:      make(map[string]Factory),
+		logger: logger,
+	}
+}
+
+// Register adds new processor factory
+func (pr *Registrar) Register(name, vendor string, pf Factory) {
+	pr.logger.Info("processor", zap.String("event", "register"), zap.String("name", name), zap.String("vendor", vendor))
+	pr.set(name, pf)
+}
+
+// GetProcessorFactory returns requested processor factory.
+func (pr *Registrar) GetProcessorFactory(name string) (Factory, bool) {
+	return pr.get(name)
+}
+
+// set registers a processor factory.
+func (pr *Registrar) set(name string, m Factory) {
+	pr.Lock()
+	defer pr.Unlock()
+	pr.p[name] = m
+}
+
+// get returns requested processor factory.
+func (pr *Registrar) get(name string) (Factory, bool) {
+	pr.RLock()
+	defer pr.RUnlock()
+	v, ok := pr.p[name]
+
+	return v, ok
+}
@@ -0,0 +1,253 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package rewrite
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/processor"
+	"github.com/yahoo/panoptes-stream/status"
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+const (
+	// TargetLabel applies the rule to a label or all labels.
+	TargetLabel = "label"
+	// TargetKey applies the rule to the key.
+	TargetKey = "key"
+	// TargetPrefix applies the rule to the prefix.
+	TargetPrefix = "prefix"
+)
+
+const (
+	// ActionRename renames the label or replaces the key or prefix.
+	ActionRename = "rename"
+	// ActionDrop drops the label or the data.
+	ActionDrop = "drop"
+	// ActionReplace replaces the regular expression matches.
+	ActionReplace = "replace"
+	// ActionCopy copies a label or the data value.
+	ActionCopy = "copy"
+	// ActionLowercase converts to lower case.
+	ActionLowercase = "lowercase"
+)
+
+var metrics = map[string]status.Metrics{
+	"droppedTotal": status.NewCounter("processor_rewrite_dropped_total", ""),
+}
+
+type rewriteConfig struct {
+	Rules []rule
+}
+
+type rule struct {
+	Target      string
+	Action      string
+	Name        string
+	To          string
+	From        string
+	Pattern     string
+	Replacement string
+
+	re *regexp.Regexp
+}
+
+// Rewrite represents label, key and prefix rewrite processor,
+// it applies the rules in order.
+type Rewrite struct {
+	rules []rule
+}
+
+// New constructs a new rewrite processor.
+func New(conf config.Processor, _ *zap.Logger) (processor.Processor, error) {
+	cfg, err := getConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Rewrite{rules: cfg.Rules}, nil
+}
+
+// Process rewrites the data, the data and its labels are copied
+// as they may be shared with the other data.
+func (r *Rewrite) Process(extDS telemetry.ExtDataStore) []telemetry.ExtDataStore {
+	ds := make(telemetry.DataStore, len(extDS.DS))
+	for k, v := range extDS.DS {
+		ds[k] = v
+	}
+
+	labels := make(map[string]string)
+	if l, ok := ds["labels"].(map[string]string); ok {
+		for k, v := range l {
+			labels[k] = v
+		}
+	}
+	ds["labels"] = labels
+
+	for _, rl := range r.rules {
+		var ok bool
+
+		switch rl.Target {
+		case TargetLabel:
+			rl.label(ds, labels)
+			ok = true
+		case TargetKey, TargetPrefix:
+			ok = rl.field(ds, labels)
+		}
+
+		if !ok {
+			metrics["droppedTotal"].Inc()
+			return nil
+		}
+	}
+
+	return []telemetry.ExtDataStore{{DS: ds, Output: extDS.Output}}
+}
+
+// label applies the rule to the labels, the rule applies to
+// all labels once the name is empty (replace and lowercase).
+func (r rule) label(ds telemetry.DataStore, labels map[string]string) {
+	switch r.Action {
+	case ActionRename:
+		if v, ok := labels[r.Name]; ok {
+			delete(labels, r.Name)
+			labels[r.To] = v
+		}
+	case ActionDrop:
+		delete(labels, r.Name)
+	case ActionCopy:
+		if v, ok := r.copy(ds, labels); ok {
+			labels[r.Name] = v
+		}
+	case ActionReplace, ActionLowercase:
+		for k, v := range labels {
+			if r.Name == "" || r.Name == k {
+				labels[k] = r.apply(v)
+			}
+		}
+	}
+}
+
+// field applies the rule to the key or prefix once it's equal to the name
+// or the name is empty, it returns false once the data should be dropped.
+func (r rule) field(ds telemetry.DataStore, labels map[string]string) bool {
+	value, _ := ds[r.Target].(string)
+
+	if r.Action == ActionDrop {
+		return !((r.Name != "" && value == r.Name) || (r.re != nil && r.re.MatchString(value)))
+	}
+
+	if r.Name != "" && value != r.Name {
+		return true
+	}
+
+	switch r.Action {
+	case ActionRename:
+		ds[r.Target] = r.To
+	case ActionCopy:
+		if v, ok := r.copy(ds, labels); ok {
+			ds[r.Target] = v
+		}
+	case ActionReplace, ActionLowercase:
+		ds[r.Target] = r.apply(value)
+	}
+
+	return true
+}
+
+// copy returns the from label value or the data value once the from is empty.
+func (r rule) copy(ds telemetry.DataStore, labels map[string]string) (string, bool) {
+	if r.From != "" {
+		v, ok := labels[r.From]
+		return v, ok
+	}
+
+	if ds["value"] == nil {
+		return "", false
+	}
+
+	return fmt.Sprintf("%v", ds["value"]), true
+}
+
+func (r rule) apply(value string) string {
+	if r.Action == ActionLowercase {
+		return strings.ToLower(value)
+	}
+
+	return r.re.ReplaceAllString(value, r.Replacement)
+}
+
+func getConfig(conf config.Processor) (*rewriteConfig, error) {
+	cfg := new(rewriteConfig)
+	b, err := json.Marshal(conf.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, r := range cfg.Rules {
+		if err := validate(r); err != nil {
+			return nil, fmt.Errorf("rule %d: %v", i, err)
+		}
+
+		if r.Pattern != "" {
+			cfg.Rules[i].re, err = regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: %v", i, err)
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+func validate(r rule) error {
+	if r.Target != TargetLabel && r.Target != TargetKey && r.Target != TargetPrefix {
+		return errors.New("unknown target: " + r.Target)
+	}
+
+	switch r.Action {
+	case ActionRename:
+		if r.To == "" || (r.Target == TargetLabel && r.Name == "") {
+			return errors.New("rename requires name and to")
+		}
+	case ActionDrop:
+		if r.Name == "" && (r.Target == TargetLabel || r.Pattern == "") {
+			return errors.New("drop requires name or pattern")
+		}
+	case ActionReplace:
+		if r.Pattern == "" {
+			return errors.New("replace requires pattern")
+		}
+	case ActionCopy:
+		if r.Target == TargetLabel && r.Name == "" {
+			return errors.New("copy requires name")
+		}
+		if r.Target != TargetLabel && r.From == "" {
+			return errors.New("copy requires from")
+		}
+	case ActionLowercase:
+	default:
+		return errors.New("unknown action: " + r.Action)
+	}
+
+	return nil
+}
+
+// Register registers rewrite as a processor at processor registrar
+func Register(processorRegistrar *processor.Registrar) {
+	status.Register(status.Labels{}, metrics)
+	processorRegistrar.Register("rewrite", "-", New)
+}
@@ -0,0 +1,109 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package rewrite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+func newExtDS(labels map[string]string) telemetry.ExtDataStore {
+	return telemetry.ExtDataStore{
+		DS: telemetry.DataStore{
+			"system_id": "core1.lax",
+			"prefix":    "/interfaces/interface/state/counters",
+			"labels":    labels,
+			"timestamp": int64(1599982184000000000),
+			"key":       "in-octets",
+			"value":     uint64(1000),
+		},
+		Output: "kafka1::interfaces",
+	}
+}
+
+func newRewrite(t *testing.T, rules ...map[string]interface{}) *Rewrite {
+	p, err := New(config.Processor{Name: "rewrite1", Service: "rewrite", Config: map[string]interface{}{"rules": rules}}, nil)
+	assert.NoError(t, err)
+
+	return p.(*Rewrite)
+}
+
+func TestRewriteLabels(t *testing.T) {
+	r := newRewrite(t,
+		map[string]interface{}{"target": "label", "action": "drop", "name": "name"},
+		map[string]interface{}{"target": "label", "action": "rename", "name": "/interfaces/interface/name", "to": "name"},
+		map[string]interface{}{"target": "label", "action": "replace", "name": "name", "pattern": "^Ethernet", "replacement": "et-"},
+		map[string]interface{}{"target": "label", "action": "lowercase"},
+		map[string]interface{}{"target": "label", "action": "copy", "name": "interface", "from": "name"},
+		map[string]interface{}{"target": "label", "action": "copy", "name": "octets"},
+	)
+
+	labels := map[string]string{"name": "0", "/interfaces/interface/name": "Ethernet1", "Site": "LAX"}
+	extDS := newExtDS(labels)
+
+	result := r.Process(extDS)
+	assert.Len(t, result, 1)
+	assert.Equal(t, map[string]string{"name": "et-1", "interface": "et-1", "Site": "lax", "octets": "1000"}, result[0].DS["labels"])
+	assert.Equal(t, "kafka1::interfaces", result[0].Output)
+
+	// the shared labels and data aren't changed
+	assert.Equal(t, map[string]string{"name": "0", "/interfaces/interface/name": "Ethernet1", "Site": "LAX"}, labels)
+	assert.Equal(t, labels, extDS.DS["labels"])
+}
+
+func TestRewriteKeyPrefix(t *testing.T) {
+	r := newRewrite(t,
+		map[string]interface{}{"target": "key", "action": "rename", "name": "in-octets", "to": "ifHCInOctets"},
+		map[string]interface{}{"target": "prefix", "action": "replace", "pattern": "^/interfaces/interface/state/", "replacement": "/if/"},
+		map[string]interface{}{"target": "key", "action": "lowercase"},
+		map[string]interface{}{"target": "prefix", "action": "copy", "name": "/if/counters", "from": "subscriptionId"},
+	)
+
+	result := r.Process(newExtDS(map[string]string{"subscriptionId": "Sub1"}))
+	assert.Len(t, result, 1)
+	assert.Equal(t, "ifhcinoctets", result[0].DS["key"])
+	assert.Equal(t, "Sub1", result[0].DS["prefix"])
+
+	// the rule doesn't apply to the other keys
+	extDS := newExtDS(nil)
+	extDS.DS["key"] = "out-octets"
+	result = r.Process(extDS)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "out-octets", result[0].DS["key"])
+	assert.Equal(t, map[string]string{}, result[0].DS["labels"])
+}
+
+func TestRewriteDrop(t *testing.T) {
+	r := newRewrite(t,
+		map[string]interface{}{"target": "key", "action": "drop", "pattern": "^in-"},
+	)
+
+	assert.Len(t, r.Process(newExtDS(nil)), 0)
+	assert.Equal(t, uint64(1), metrics["droppedTotal"].Get())
+
+	extDS := newExtDS(nil)
+	extDS.DS["key"] = "out-octets"
+	assert.Len(t, r.Process(extDS), 1)
+}
+
+func TestGetConfig(t *testing.T) {
+	for _, rule := range []map[string]interface{}{
+		{"target": "value", "action": "drop", "name": "name"},
+		{"target": "label", "action": "move", "name": "name"},
+		{"target": "label", "action": "rename", "name": "name"},
+		{"target": "label", "action": "drop"},
+		{"target": "key", "action": "drop"},
+		{"target": "key", "action": "replace"},
+		{"target": "key", "action": "replace", "pattern": "("},
+		{"target": "key", "action": "copy"},
+		{"target": "label", "action": "copy"},
+	} {
+		_, err := New(config.Processor{Config: map[string]interface{}{"rules": []interface{}{rule}}}, nil)
+		assert.Error(t, err, rule)
+	}
+}
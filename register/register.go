@@ -6,13 +6,20 @@ package register
 import (
 	"github.com/yahoo/panoptes-stream/database"
 	"github.com/yahoo/panoptes-stream/database/tsdb"
+	"github.com/yahoo/panoptes-stream/processor"
+	"github.com/yahoo/panoptes-stream/processor/aggregate"
+	"github.com/yahoo/panoptes-stream/processor/rate"
+	"github.com/yahoo/panoptes-stream/processor/rewrite"
 	"github.com/yahoo/panoptes-stream/producer"
 	"github.com/yahoo/panoptes-stream/producer/console"
 	"github.com/yahoo/panoptes-stream/producer/mqueue"
 	"github.com/yahoo/panoptes-stream/telemetry"
 	"github.com/yahoo/panoptes-stream/telemetry/arista"
 	"github.com/yahoo/panoptes-stream/telemetry/cisco"
+	"github.com/yahoo/panoptes-stream/telemetry/dialout"
+	"github.com/yahoo/panoptes-stream/telemetry/generic"
 	"github.com/yahoo/panoptes-stream/telemetry/juniper"
+	"github.com/yahoo/panoptes-stream/telemetry/nokia"
 )
 
 // Telemetry registers all available telemetries
@@ -20,6 +27,15 @@ func Telemetry(telemetryRegistrar *telemetry.Registrar) {
 	juniper.Register(telemetryRegistrar)
 	cisco.Register(telemetryRegistrar)
 	arista.Register(telemetryRegistrar)
+	nokia.Register(telemetryRegistrar)
+	generic.Register(telemetryRegistrar)
+}
+
+// Dialout registers all available dial-out telemetries
+func Dialout(dialoutRegistrar *dialout.Registrar) {
+	cisco.RegisterDialout(dialoutRegistrar)
+	generic.RegisterDialout(dialoutRegistrar)
+	juniper.RegisterDialout(dialoutRegistrar)
 }
 
 // Producer registers all available producers
@@ -32,3 +48,10 @@ func Producer(producerRegistrar *producer.Registrar) {
 func Database(databaseRegistrar *database.Registrar) {
 	tsdb.Register(databaseRegistrar)
 }
+
+// Processor registers all available processors
+func Processor(processorRegistrar *processor.Registrar) {
+	rate.Register(processorRegistrar)
+	aggregate.Register(processorRegistrar)
+	rewrite.Register(processorRegistrar)
+}
@@ -0,0 +1,333 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package secret
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/yahoo/panoptes-stream/config"
+)
+
+// cacheRefreshInterval is the interval which the expired secrets are checked for changes.
+const cacheRefreshInterval = 10 * time.Second
+
+// cacheTTL is the maximum time which a remote secret is cached,
+// the secrets with a shorter lease are refreshed once their lease expired.
+var cacheTTL = time.Duration(config.GetEnvInt("SECRET_CACHE_TTL", 300)) * time.Second
+
+// cacheIdleTTLs is the number of TTLs which a secret is cached without being read,
+// the idle secrets are evicted unless they're held e.g. by a device subscription.
+const cacheIdleTTLs = 3
+
+// LeaseSecret represents a secret engine which returns the secrets with their lease duration.
+type LeaseSecret interface {
+	GetSecretsLease(string) (map[string][]byte, time.Duration, error)
+}
+
+// WatchSecret represents a secret engine which notifies the secrets changes e.g. file,
+// the watch returns a function which stops watching.
+type WatchSecret interface {
+	Watch(string, func()) (func(), error)
+}
+
+type cacheEntry struct {
+	// read is the last read time in unix nanoseconds
+	read    int64
+	sType   string
+	path    string
+	secrets map[string][]byte
+	digest  [sha256.Size]byte
+	expire  time.Time
+}
+
+var cache = struct {
+	sync.RWMutex
+	entries map[string]*cacheEntry
+	notify  []chan<- string
+	watched map[string]func()
+	held    map[string]int
+	group   singleflight.Group
+	once    sync.Once
+}{
+	entries: make(map[string]*cacheEntry),
+	watched: make(map[string]func()),
+	held:    make(map[string]int),
+}
+
+// GetSecrets returns the remote secrets from the cache, they're fetched
+// from the secret engine once they're not cached or expired.
+func GetSecrets(sType, path string) (map[string][]byte, error) {
+	key := SecretKey(sType, path)
+
+	cache.RLock()
+	entry, ok := cache.entries[key]
+	cache.RUnlock()
+
+	if ok && time.Now().Before(entry.expire) {
+		atomic.StoreInt64(&entry.read, time.Now().UnixNano())
+		return copySecrets(entry.secrets), nil
+	}
+
+	v, err, _ := cache.group.Do(key, func() (interface{}, error) {
+		entry, err := fetchSecrets(sType, path)
+		if err != nil {
+			return nil, err
+		}
+
+		cache.Lock()
+		cache.entries[key] = entry
+		cache.Unlock()
+
+		return entry, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return copySecrets(v.(*cacheEntry).secrets), nil
+}
+
+// Notify relays the key of the changed secrets e.g. __vault::path to the channel,
+// the cached secrets are checked once their TTL expired. it returns a function
+// which stops relaying to the channel.
+func Notify(ch chan<- string) func() {
+	cache.Lock()
+	cache.notify = append(cache.notify, ch)
+	cache.Unlock()
+
+	cache.once.Do(func() {
+		go func() {
+			for {
+				<-time.After(cacheRefreshInterval)
+				Refresh()
+			}
+		}()
+	})
+
+	return func() {
+		cache.Lock()
+		defer cache.Unlock()
+
+		// the notify list may be in use by a refresh, it's replaced
+		var notify []chan<- string
+		for _, c := range cache.notify {
+			if c != ch {
+				notify = append(notify, c)
+			}
+		}
+		cache.notify = notify
+	}
+}
+
+// Hold keeps the secrets cached and refreshed regardless of their last read
+// e.g. the secrets which a device subscription depends on to re-authenticate.
+func Hold(keys ...string) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	for _, key := range keys {
+		cache.held[key]++
+	}
+}
+
+// Release releases the held secrets, they're evicted once they're idle.
+func Release(keys ...string) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	for _, key := range keys {
+		if cache.held[key]--; cache.held[key] < 1 {
+			delete(cache.held, key)
+		}
+	}
+}
+
+// Refresh evicts the idle secrets, fetches the expired secrets and notifies the changed
+// ones. the cached secrets are kept if the secret engine is not available until they're idle.
+func Refresh() {
+	var expired []*cacheEntry
+
+	evict()
+
+	cache.RLock()
+	for _, entry := range cache.entries {
+		if time.Now().After(entry.expire) {
+			expired = append(expired, entry)
+		}
+	}
+	cache.RUnlock()
+
+	for _, entry := range expired {
+		refresh(entry.sType, entry.path)
+	}
+}
+
+// evict removes the secrets which they're not held and not read
+// within the idle TTLs along with their watches.
+func evict() {
+	var stops []func()
+
+	cache.Lock()
+	for key, entry := range cache.entries {
+		read := time.Unix(0, atomic.LoadInt64(&entry.read))
+		if cache.held[key] > 0 || time.Since(read) < cacheIdleTTLs*cacheTTL {
+			continue
+		}
+
+		delete(cache.entries, key)
+
+		if stop, ok := cache.watched[key]; ok {
+			stops = append(stops, stop)
+			delete(cache.watched, key)
+		}
+	}
+	cache.Unlock()
+
+	// the watches may wait for the cache lock to refresh
+	for _, stop := range stops {
+		stop()
+	}
+}
+
+// refresh fetches the secret and notifies if it changed,
+// the evicted secrets are not cached again.
+func refresh(sType, path string) {
+	key := SecretKey(sType, path)
+
+	cache.RLock()
+	_, ok := cache.entries[key]
+	cache.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	newEntry, err := fetchSecrets(sType, path)
+	if err != nil {
+		return
+	}
+
+	cache.Lock()
+	entry, ok := cache.entries[key]
+	if ok {
+		newEntry.read = atomic.LoadInt64(&entry.read)
+		cache.entries[key] = newEntry
+	}
+	notify := cache.notify
+	cache.Unlock()
+
+	if !ok || newEntry.digest == entry.digest {
+		return
+	}
+
+	for _, ch := range notify {
+		select {
+		case ch <- key:
+		default:
+		}
+	}
+}
+
+// SecretKey returns the remote secret key e.g. __vault::path.
+func SecretKey(sType, path string) string {
+	return fmt.Sprintf("__%s::%s", sType, path)
+}
+
+func fetchSecrets(sType, path string) (*cacheEntry, error) {
+	var (
+		secrets map[string][]byte
+		lease   time.Duration
+	)
+
+	sec, err := GetSecretEngine(sType)
+	if err != nil {
+		return nil, err
+	}
+
+	if ws, ok := sec.(WatchSecret); ok {
+		watchSecrets(ws, sType, path)
+	}
+
+	if ls, ok := sec.(LeaseSecret); ok {
+		secrets, lease, err = ls.GetSecretsLease(path)
+	} else {
+		secrets, err = sec.GetSecrets(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := cacheTTL
+	if lease > 0 && lease < ttl {
+		ttl = lease
+	}
+
+	return &cacheEntry{
+		read:    time.Now().UnixNano(),
+		sType:   sType,
+		path:    path,
+		secrets: secrets,
+		digest:  digest(secrets),
+		expire:  time.Now().Add(ttl),
+	}, nil
+}
+
+// watchSecrets watches the secret once, the changes are
+// applied immediately instead of waiting for the TTL.
+func watchSecrets(ws WatchSecret, sType, path string) {
+	key := SecretKey(sType, path)
+
+	cache.Lock()
+	defer cache.Unlock()
+
+	if _, ok := cache.watched[key]; ok {
+		return
+	}
+
+	stop, err := ws.Watch(path, func() {
+		refresh(sType, path)
+	})
+	if err == nil {
+		cache.watched[key] = stop
+	}
+}
+
+func digest(secrets map[string][]byte) [sha256.Size]byte {
+	var keys []string
+
+	for k := range secrets {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write(secrets[k])
+		h.Write([]byte{0})
+	}
+
+	var d [sha256.Size]byte
+	copy(d[:], h.Sum(nil))
+
+	return d
+}
+
+func copySecrets(secrets map[string][]byte) map[string][]byte {
+	result := make(map[string][]byte, len(secrets))
+	for k, v := range secrets {
+		result[k] = v
+	}
+
+	return result
+}
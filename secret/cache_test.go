@@ -0,0 +1,165 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretsCache(t *testing.T) {
+	os.Setenv("PANOPTES_VAULT_TLSCONFIG_ENABLED", "true")
+	os.Setenv("PANOPTES_VAULT_TLSCONFIG_INSECURESKIPVERIFY", "true")
+
+	cluster := createVaultTestCluster(t)
+	defer cluster.Cleanup()
+
+	client := cluster.Cores[0].Client
+
+	os.Setenv("PANOPTES_VAULT_TOKEN", client.Token())
+
+	path := "secrets/v1/rotate"
+	client.Logical().Write(path, map[string]interface{}{"admin": "secret-1"})
+
+	secrets, err := GetSecrets("vault", path)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret-1", string(secrets["admin"]))
+
+	// the cached secrets are not mutable
+	secrets["admin"] = []byte("changed")
+
+	client.Logical().Write(path, map[string]interface{}{"admin": "secret-2"})
+
+	secrets, err = GetSecrets("vault", path)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret-1", string(secrets["admin"]))
+
+	ch := make(chan string, 1)
+	unsubscribe := Notify(ch)
+
+	// expire
+	cache.Lock()
+	cache.entries[SecretKey("vault", path)].expire = time.Now().Add(-time.Second)
+	cache.Unlock()
+
+	Refresh()
+
+	select {
+	case key := <-ch:
+		assert.Equal(t, "__vault::secrets/v1/rotate", key)
+	default:
+		t.Fatal("expect secret change notification")
+	}
+
+	secrets, err = GetSecrets("vault", path)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret-2", string(secrets["admin"]))
+
+	// expired without change
+	cache.Lock()
+	cache.entries[SecretKey("vault", path)].expire = time.Now().Add(-time.Second)
+	cache.Unlock()
+
+	Refresh()
+
+	select {
+	case <-ch:
+		t.Fatal("unexpected secret change notification")
+	default:
+	}
+
+	// unsubscribed
+	client.Logical().Write(path, map[string]interface{}{"admin": "secret-3"})
+
+	cache.Lock()
+	cache.entries[SecretKey("vault", path)].expire = time.Now().Add(-time.Second)
+	cache.Unlock()
+
+	unsubscribe()
+	Refresh()
+
+	select {
+	case <-ch:
+		t.Fatal("unexpected secret change notification after unsubscribe")
+	default:
+	}
+}
+
+func TestSecretsCacheEviction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "creds")
+	if err := ioutil.WriteFile(path, []byte("admin: secret-1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	key := SecretKey("file", path)
+	idle := func() {
+		cache.Lock()
+		atomic.StoreInt64(&cache.entries[key].read, time.Now().Add(-cacheIdleTTLs*cacheTTL).UnixNano())
+		cache.Unlock()
+	}
+	cached := func() bool {
+		cache.RLock()
+		defer cache.RUnlock()
+
+		_, ok := cache.entries[key]
+		_, watched := cache.watched[key]
+		assert.Equal(t, ok, watched)
+
+		return ok
+	}
+
+	_, err = GetSecrets("file", path)
+	assert.NoError(t, err)
+
+	// read recently
+	Refresh()
+	assert.True(t, cached())
+
+	// idle but held
+	Hold(key)
+	idle()
+	Refresh()
+	assert.True(t, cached())
+
+	// idle and released
+	Release(key)
+	Refresh()
+	assert.False(t, cached())
+
+	// the evicted secret is not cached again by a refresh
+	refresh("file", path)
+	assert.False(t, cached())
+
+	secrets, err := GetSecrets("file", path)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret-1", string(secrets["admin"]))
+	assert.True(t, cached())
+
+	// the idle secret is evicted even if the secret engine is not available
+	os.Remove(path)
+	idle()
+	Refresh()
+	assert.False(t, cached())
+}
+
+func TestSecretsDigest(t *testing.T) {
+	a := digest(map[string][]byte{"user": []byte("pass"), "user2": []byte("pass2")})
+	b := digest(map[string][]byte{"user2": []byte("pass2"), "user": []byte("pass")})
+	c := digest(map[string][]byte{"user": []byte("pass2"), "user2": []byte("pass")})
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+}
@@ -0,0 +1,289 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package file
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"golang.org/x/crypto/pkcs12"
+	"gopkg.in/yaml.v3"
+)
+
+// dirAliases maps the Kubernetes TLS secret keys to the TLS secret keys.
+var dirAliases = map[string]string{
+	"tls.crt": "cert",
+	"tls.key": "key",
+	"ca.crt":  "ca",
+}
+
+// File represents the file secret engine, the file can be a key/value
+// file (YAML, JSON or key=value lines), a PEM bundle or a PKCS#12 file.
+type File struct{}
+
+// Dir represents the directory secret engine, every file is a key
+// and its content is the value e.g. Kubernetes secret volume.
+type Dir struct{}
+
+// New constructs a new file secret engine
+func New() *File {
+	return &File{}
+}
+
+// NewDir constructs a new directory secret engine
+func NewDir() *Dir {
+	return &Dir{}
+}
+
+// GetSecrets returns the file secrets as key value, the PEM bundle and the PKCS#12
+// are converted to cert, key and ca (PEM encoded) like the Vault TLS secrets.
+func (f *File) GetSecrets(path string) (map[string][]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("file: %v", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".p12", ".pfx":
+		return getPKCS12Secrets(data, os.Getenv("PANOPTES_SECRET_PKCS12_PASSWORD"))
+	}
+
+	if bytes.Contains(data, []byte("-----BEGIN ")) {
+		return getPEMSecrets(data)
+	}
+
+	return getKeyValueSecrets(data)
+}
+
+// Watch calls the function once the file changed, it returns a function which stops watching.
+func (f *File) Watch(path string, fn func()) (func(), error) {
+	name := filepath.Base(path)
+
+	return watch(filepath.Dir(path), func(event fsnotify.Event) bool {
+		base := filepath.Base(event.Name)
+		// Kubernetes updates the volume atomically by ..data symlink
+		return base == name || strings.HasPrefix(base, "..")
+	}, fn)
+}
+
+// GetSecrets returns the directory files as key value, the hidden files are ignored.
+func (d *Dir) GetSecrets(path string) (map[string][]byte, error) {
+	var result = make(map[string][]byte)
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("dir: %v", err)
+	}
+
+	for _, file := range files {
+		name := file.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		// the symlinks (Kubernetes) are resolved by stat
+		info, err := os.Stat(filepath.Join(path, name))
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			return nil, fmt.Errorf("dir: %v", err)
+		}
+
+		result[name] = bytes.TrimRight(data, "\r\n")
+
+		if alias, ok := dirAliases[name]; ok {
+			result[alias] = result[name]
+		}
+	}
+
+	if len(result) < 1 {
+		return nil, fmt.Errorf("dir: path %s has no secret", path)
+	}
+
+	return result, nil
+}
+
+// Watch calls the function once a file at the directory changed, it returns a function which stops watching.
+func (d *Dir) Watch(path string, fn func()) (func(), error) {
+	return watch(path, func(fsnotify.Event) bool { return true }, fn)
+}
+
+func watch(dir string, match func(fsnotify.Event) bool, fn func()) (func(), error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if event.Op&fsnotify.Chmod == 0 && match(event) {
+					fn()
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		watcher.Close()
+	}, nil
+}
+
+// getPEMSecrets returns the private key as key, the CA certificates
+// as ca and the rest of the certificates (leaf and intermediates) as cert.
+func getPEMSecrets(data []byte) (map[string][]byte, error) {
+	var (
+		cert, key, ca bytes.Buffer
+		blocks        []*pem.Block
+	)
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	for _, block := range blocks {
+		switch {
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			pem.Encode(&key, block)
+		case block.Type == "CERTIFICATE":
+			c, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("file: %v", err)
+			}
+
+			if c.IsCA {
+				pem.Encode(&ca, block)
+			} else {
+				pem.Encode(&cert, block)
+			}
+		}
+	}
+
+	return getTLSSecrets(cert.Bytes(), key.Bytes(), ca.Bytes())
+}
+
+// getPKCS12Secrets returns the private key as key, the certificate
+// which belongs to the private key as cert and the rest as ca.
+func getPKCS12Secrets(data []byte, password string) (map[string][]byte, error) {
+	var cert, key, ca bytes.Buffer
+
+	blocks, err := pkcs12.ToPEM(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("file: %v", err)
+	}
+
+	keyID := ""
+	for _, block := range blocks {
+		if strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			keyID = block.Headers["localKeyId"]
+			pem.Encode(&key, &pem.Block{Type: block.Type, Bytes: block.Bytes})
+		}
+	}
+
+	for _, block := range blocks {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		b := &pem.Block{Type: block.Type, Bytes: block.Bytes}
+		if (keyID != "" && block.Headers["localKeyId"] == keyID) || (keyID == "" && cert.Len() == 0) {
+			pem.Encode(&cert, b)
+		} else {
+			pem.Encode(&ca, b)
+		}
+	}
+
+	return getTLSSecrets(cert.Bytes(), key.Bytes(), ca.Bytes())
+}
+
+func getTLSSecrets(cert, key, ca []byte) (map[string][]byte, error) {
+	var result = make(map[string][]byte)
+
+	if len(cert) > 0 {
+		result["cert"] = cert
+	}
+
+	if len(key) > 0 {
+		result["key"] = key
+	}
+
+	if len(ca) > 0 {
+		result["ca"] = ca
+	}
+
+	if len(result) < 1 {
+		return nil, errors.New("file: certificate or key not available")
+	}
+
+	return result, nil
+}
+
+// getKeyValueSecrets returns the YAML / JSON map or the key=value lines as key value.
+func getKeyValueSecrets(data []byte) (map[string][]byte, error) {
+	var (
+		m      map[string]interface{}
+		result = make(map[string][]byte)
+	)
+
+	if err := yaml.Unmarshal(data, &m); err == nil && len(m) > 0 {
+		for k, v := range m {
+			result[k] = []byte(fmt.Sprint(v))
+		}
+
+		return result, nil
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.New("file: invalid key value format")
+		}
+
+		result[strings.TrimSpace(kv[0])] = []byte(strings.TrimSpace(kv[1]))
+	}
+
+	if len(result) < 1 {
+		return nil, errors.New("file: secret not available")
+	}
+
+	return result, nil
+}
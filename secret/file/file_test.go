@@ -0,0 +1,244 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package file
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testPKCS12 is a PKCS#12 (3DES) with a leaf certificate, its key and the CA (password: panoptes).
+const testPKCS12 = `
+	MIIE4gIBAzCCBKgGCSqGSIb3DQEHAaCCBJkEggSVMIIEkTCCA4cGCSqGSIb3DQEHBqCCA3gwggN0
+	AgEAMIIDbQYJKoZIhvcNAQcBMBwGCiqGSIb3DQEMAQMwDgQIZBkY0zmvuBcCAggAgIIDQBX9zkCy
+	MQiOFesFqMuOEWyEQCsrvvu+t3HXlBje4YJt6f/tT5t2J5H/gYGhFPgOtOKfTPbAY/ZHSD+1wx29
+	7IoG8hWzbUE8DTij4RhhB4bFNYGdZcr8dYuUHKDt136eV00RcDMZ5MPU2KhZP9DrJ3xTTIzpfndl
+	Cf+czOpwQdlQNK7yhYW1+Y5o5rQICE+3rzpUVvfIWZmGmUjM9a/IZ0DULPPoJJ+Htv9rn71OMYLS
+	ATjdWAb5T5AMtiVuiYQMvpZziRjZV3w6Dq+vrQAhGk7wIvmv2kPG8+x53cJJQgId15Ubxco1zerj
+	acUmo5m8xGDs3dSb+hLnK/VNjXElCR7+8BCI6klhp3PLWgVjVj0PsMdVOad7epyDl6ppxd2Njy6W
+	acSHfXkhLzyTUVeme1/NiKAn6qVLdItxTPgEWiq+gNS8+ir6EU7gM6jL01Ku0ku1Re/KNnZTxHEd
+	3CsJI2sxExG8KW7N3TbIl4XOzFRlNUAHhjfmmF0NParyGQMQ+N9h5mD6mV0UQnkK9JElQzVPptWK
+	ouMtzId07oxjF7AIrBV/dYzgsmntYUbDIHyNLVMm+xyH2Stl7Fl9ECozuOPK4iRSFqva4sUsROtK
+	R00q+D0XHoXBpKWCaX+egkh9JQVNXQb4gQUuE0C2HgaNcMyjclptuInDaE1F48qM8X67NtC612fy
+	AppFD1qaeRXXd039TyPcIELSTyHdSPSPv37oGtCibYBB/ML2BM60Ja4uIR7CZDvskYe0u9yBT5ci
+	eECMtwKKZClWbQNCAQ2QdfWXTUb8hNfboYVAyptvD9tKngWO8DpXo4Rklw+6CFfemwVWKgLYWJZ5
+	pztX6MP/ufoJMpSdW6rQT8U6rP/y62alOZsoFM/HMH6/5bh+TU1Tl4AjVncM9QSM6yu33WPHRElc
+	czoEr2AYvF8mV4GpOTWZQvTwRtG3GdrqvrfNCTI1kL0YWX0WQIF8fjOAz/7AGY3cNt0CmkJD9B2G
+	FFdwPfEiduWwMApigCJc7PlBHuyUhtkbHjH0JyY31ikjOl4VPkcl3NTm8RcqClPlNUMNK1zqk+/y
+	yZ8h2w+gmIMV/33f2fIoB3wEm2X4TWeYXWvB/P8wggECBgkqhkiG9w0BBwGggfQEgfEwge4wgesG
+	CyqGSIb3DQEMCgECoIG0MIGxMBwGCiqGSIb3DQEMAQMwDgQIr+lSTq83JJoCAggABIGQa5WRM0CH
+	UyhuJiq0AYB3fy3rzgW1GYg8wcute5nghUNNLUPtoRu1cHI023/sIsbRtQlAtog49DTLIYhN9rfK
+	AkK8e0hjfCwfwz2Oehm8APA9zAnZV4Uo+9fkQWSGdA8UbQmiQ47JsuNelKli8Z41fWfxRRYXHX21
+	+8TvHhzy9x2tFWl12FvG0UP5W2RQVfgQMSUwIwYJKoZIhvcNAQkVMRYEFJjJIN2+F3juz2Q9QS97
+	W5EzVchRMDEwITAJBgUrDgMCGgUABBSgUNisJq+0X4WyvR7jiebb2SKVkAQIVIPgHVrs4i4CAggA
+`
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "panoptes-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	return dir
+}
+
+func writeFile(t *testing.T, path, data string) {
+	if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKeyValueFile(t *testing.T) {
+	dir := tempDir(t)
+	f := New()
+
+	writeFile(t, filepath.Join(dir, "creds.yaml"), "admin: topsecret\n")
+	secrets, err := f.GetSecrets(filepath.Join(dir, "creds.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"admin": []byte("topsecret")}, secrets)
+
+	writeFile(t, filepath.Join(dir, "creds.json"), `{"admin": "topsecret"}`)
+	secrets, err = f.GetSecrets(filepath.Join(dir, "creds.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, "topsecret", string(secrets["admin"]))
+
+	writeFile(t, filepath.Join(dir, "creds.env"), "# device credentials\nadmin = top=secret\n")
+	secrets, err = f.GetSecrets(filepath.Join(dir, "creds.env"))
+	assert.NoError(t, err)
+	assert.Equal(t, "top=secret", string(secrets["admin"]))
+
+	writeFile(t, filepath.Join(dir, "invalid"), "topsecret")
+	_, err = f.GetSecrets(filepath.Join(dir, "invalid"))
+	assert.Error(t, err)
+
+	_, err = f.GetSecrets(filepath.Join(dir, "notexist"))
+	assert.Error(t, err)
+}
+
+func TestPEMBundle(t *testing.T) {
+	var bundle bytes.Buffer
+
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "panoptes-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "core1.lax"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caTemplate, &key.PublicKey, caKey)
+	assert.NoError(t, err)
+
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+
+	pem.Encode(&bundle, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	pem.Encode(&bundle, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	pem.Encode(&bundle, &pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+
+	path := filepath.Join(tempDir(t), "bundle.pem")
+	writeFile(t, path, bundle.String())
+
+	secrets, err := New().GetSecrets(path)
+	assert.NoError(t, err)
+	assert.Len(t, secrets, 3)
+
+	_, err = tls.X509KeyPair(secrets["cert"], secrets["key"])
+	assert.NoError(t, err)
+
+	block, _ := pem.Decode(secrets["ca"])
+	assert.Equal(t, caDER, block.Bytes)
+}
+
+func TestPKCS12File(t *testing.T) {
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(testPKCS12), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(tempDir(t), "device.p12")
+	writeFile(t, path, string(data))
+
+	os.Setenv("PANOPTES_SECRET_PKCS12_PASSWORD", "panoptes")
+	defer os.Unsetenv("PANOPTES_SECRET_PKCS12_PASSWORD")
+
+	secrets, err := New().GetSecrets(path)
+	assert.NoError(t, err)
+
+	cert, err := tls.X509KeyPair(secrets["cert"], secrets["key"])
+	assert.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "core1.lax", leaf.Subject.CommonName)
+
+	block, _ := pem.Decode(secrets["ca"])
+	ca, err := x509.ParseCertificate(block.Bytes)
+	assert.NoError(t, err)
+	assert.Equal(t, "panoptes-ca", ca.Subject.CommonName)
+
+	os.Setenv("PANOPTES_SECRET_PKCS12_PASSWORD", "wrong")
+	_, err = New().GetSecrets(path)
+	assert.Error(t, err)
+}
+
+func TestDir(t *testing.T) {
+	dir := tempDir(t)
+
+	writeFile(t, filepath.Join(dir, "admin"), "topsecret\n")
+	writeFile(t, filepath.Join(dir, "tls.crt"), "cert")
+	writeFile(t, filepath.Join(dir, ".hidden"), "hidden")
+	os.Mkdir(filepath.Join(dir, "..data"), 0700)
+
+	secrets, err := NewDir().GetSecrets(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"admin":   []byte("topsecret"),
+		"tls.crt": []byte("cert"),
+		"cert":    []byte("cert"),
+	}, secrets)
+
+	_, err = NewDir().GetSecrets(filepath.Join(dir, "..data"))
+	assert.Error(t, err)
+}
+
+func TestWatch(t *testing.T) {
+	dir := tempDir(t)
+	path := filepath.Join(dir, "creds.yaml")
+	writeFile(t, path, "admin: secret1\n")
+
+	ch := make(chan struct{}, 10)
+	stop, err := New().Watch(path, func() { ch <- struct{}{} })
+	assert.NoError(t, err)
+
+	// not related file
+	writeFile(t, filepath.Join(dir, "other"), "other")
+	writeFile(t, path, "admin: secret2\n")
+
+	select {
+	case <-ch:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expect file change notification")
+	}
+
+	dch := make(chan struct{}, 10)
+	dstop, err := NewDir().Watch(dir, func() { dch <- struct{}{} })
+	assert.NoError(t, err)
+	defer dstop()
+
+	writeFile(t, filepath.Join(dir, "admin"), "topsecret")
+
+	select {
+	case <-dch:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expect directory change notification")
+	}
+
+	// stopped
+	stop()
+	time.Sleep(100 * time.Millisecond)
+	for len(ch) > 0 {
+		<-ch
+	}
+
+	writeFile(t, path, "admin: secret3\n")
+
+	select {
+	case <-ch:
+		t.Fatal("unexpected file change notification after stop")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	_, err = New().Watch(filepath.Join(dir, "notexist", "creds"), func() {})
+	assert.Error(t, err)
+}
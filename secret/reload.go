@@ -0,0 +1,217 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package secret
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/status"
+)
+
+// tlsReloader holds the latest loaded server certificate and its TLS config.
+type tlsReloader struct {
+	name       string
+	cfg        config.TLSConfig
+	clientAuth tls.ClientAuthType
+
+	cert      *tls.Certificate
+	tlsConfig *tls.Config
+	sync.RWMutex
+
+	metrics map[string]status.Metrics
+}
+
+// GetTLSServerReloadConfig returns the TLS server config which serves the latest certificate,
+// it's reloaded once the local files or the remote secrets (TTL) changed until the context is done.
+// the client certificates are verified by the CA if the client auth is requested.
+// the metrics are registered per server name until the returned close function is called.
+func GetTLSServerReloadConfig(ctx context.Context, name string, cfg *config.TLSConfig, clientAuth tls.ClientAuthType) (*tls.Config, func(), error) {
+	var metrics = make(map[string]status.Metrics)
+
+	metrics["certExpiry"] = status.NewGauge("tls_cert_expiry_timestamp_seconds", "TLS server certificate expiry time in unix seconds")
+	metrics["certReloadsTotal"] = status.NewCounter("tls_cert_reloads_total", "TLS server certificate reloads")
+	metrics["certReloadFailuresTotal"] = status.NewCounter("tls_cert_reload_failures_total", "TLS server certificate reload failures")
+
+	r := &tlsReloader{
+		name:       name,
+		cfg:        *cfg,
+		clientAuth: clientAuth,
+		metrics:    metrics,
+	}
+
+	if err := r.load(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := r.watch(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	status.Register(r.labels(), r.metrics)
+
+	tlsConfig := &tls.Config{
+		GetCertificate: r.getCertificate,
+	}
+
+	if clientAuth != tls.NoClientCert {
+		tlsConfig.GetConfigForClient = r.getConfigForClient
+	}
+
+	var once sync.Once
+	closeFunc := func() {
+		once.Do(func() {
+			status.Unregister(r.labels(), r.metrics)
+		})
+	}
+
+	return tlsConfig, closeFunc, nil
+}
+
+func (r *tlsReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.cert, nil
+}
+
+func (r *tlsReloader) getConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.tlsConfig, nil
+}
+
+// load loads the certificate, the current one is kept if it fails.
+func (r *tlsReloader) load() error {
+	// the local config sets the key file if it's not available
+	cfg := r.cfg
+
+	tlsConfig, err := GetTLSServerConfig(&cfg)
+	if err != nil {
+		return err
+	}
+
+	if len(tlsConfig.Certificates) < 1 {
+		return errors.New("certificate not available")
+	}
+
+	cert := tlsConfig.Certificates[0]
+	cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return err
+	}
+
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if r.clientAuth != tls.NoClientCert {
+		if tlsConfig.RootCAs == nil {
+			return errors.New("client auth requires CA")
+		}
+
+		serverConfig.ClientCAs = tlsConfig.RootCAs
+		serverConfig.ClientAuth = r.clientAuth
+	}
+
+	r.metrics["certExpiry"].Set(uint64(cert.Leaf.NotAfter.Unix()))
+
+	r.Lock()
+	r.cert = &cert
+	r.tlsConfig = serverConfig
+	r.Unlock()
+
+	return nil
+}
+
+func (r *tlsReloader) reload() {
+	if err := r.load(); err != nil {
+		r.metrics["certReloadFailuresTotal"].Inc()
+		return
+	}
+
+	r.metrics["certReloadsTotal"].Inc()
+}
+
+func (r *tlsReloader) labels() status.Labels {
+	return status.Labels{"server": r.name}
+}
+
+// watch reloads the certificate once the remote secret changed
+// (notified by the secrets cache) or the local files changed.
+func (r *tlsReloader) watch(ctx context.Context) error {
+	if sType, path, ok := ParseRemoteSecretInfo(r.cfg.CertFile); ok {
+		key := SecretKey(sType, path)
+		ch := make(chan string, 1)
+		unsubscribe := Notify(ch)
+
+		// the secret is kept cached to be notified once it changed
+		Hold(key)
+
+		go func() {
+			defer unsubscribe()
+			defer Release(key)
+
+			for {
+				select {
+				case k := <-ch:
+					if k == key {
+						r.reload()
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	names := make(map[string]bool)
+	for _, file := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.CAFile} {
+		if file == "" {
+			continue
+		}
+
+		names[filepath.Base(file)] = true
+		if err := watcher.Add(filepath.Dir(file)); err != nil {
+			watcher.Close()
+			return err
+		}
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		for {
+			select {
+			case event := <-watcher.Events:
+				base := filepath.Base(event.Name)
+				// Kubernetes updates the volume atomically by ..data symlink
+				if event.Op&fsnotify.Chmod == 0 && (names[base] || strings.HasPrefix(base, "..")) {
+					r.reload()
+				}
+			case <-watcher.Errors:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,192 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package secret
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yahoo/panoptes-stream/config"
+)
+
+func genCertKey(t *testing.T, serial int64, notAfter time.Time) (string, string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject: pkix.Name{
+			Organization: []string{"panoptes"},
+		},
+		NotBefore:   time.Now(),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	derCertBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	assert.NoError(t, err)
+
+	cert := &bytes.Buffer{}
+	pem.Encode(cert, &pem.Block{Type: "CERTIFICATE", Bytes: derCertBytes})
+
+	key := &bytes.Buffer{}
+	pem.Encode(key, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+
+	return cert.String(), key.String()
+}
+
+func waitSerial(t *testing.T, tlsConfig *tls.Config, serial int64) {
+	for i := 0; i < 40; i++ {
+		cert, err := tlsConfig.GetCertificate(nil)
+		assert.NoError(t, err)
+
+		if cert.Leaf.SerialNumber.Int64() == serial {
+			return
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	t.Fatalf("expect to reload the certificate serial %d", serial)
+}
+
+// getMetric returns the registered metric value of the server.
+func getMetric(t *testing.T, name, server string) float64 {
+	value, ok := lookupMetric(t, name, server)
+	if !ok {
+		t.Fatalf("metric %s not found", name)
+	}
+
+	return value
+}
+
+func hasMetric(t *testing.T, name, server string) bool {
+	_, ok := lookupMetric(t, name, server)
+	return ok
+}
+
+func lookupMetric(t *testing.T, name, server string) (float64, bool) {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "server" && label.GetValue() == server {
+					if m.GetGauge() != nil {
+						return m.GetGauge().GetValue(), true
+					}
+					return m.GetCounter().GetValue(), true
+				}
+			}
+		}
+	}
+
+	return 0, false
+}
+
+func TestGetTLSServerReloadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "panoptes")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	notAfter := time.Now().Add(time.Hour).Truncate(time.Second)
+	cert, key := genCertKey(t, 1, notAfter)
+	ioutil.WriteFile(certFile, []byte(cert), 0644)
+	ioutil.WriteFile(keyFile, []byte(key), 0644)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := &config.TLSConfig{CertFile: certFile, KeyFile: keyFile}
+	tlsConfig, closeFunc, err := GetTLSServerReloadConfig(ctx, "test", cfg, tls.NoClientCert)
+	assert.NoError(t, err)
+	assert.Nil(t, tlsConfig.GetConfigForClient)
+
+	waitSerial(t, tlsConfig, 1)
+	assert.Equal(t, float64(notAfter.Unix()), getMetric(t, "panoptes_tls_cert_expiry_timestamp_seconds", "test"))
+
+	// the certificate is reloaded once both files changed
+	cert, key = genCertKey(t, 2, notAfter.Add(time.Hour))
+	ioutil.WriteFile(certFile, []byte(cert), 0644)
+	ioutil.WriteFile(keyFile, []byte(key), 0644)
+
+	waitSerial(t, tlsConfig, 2)
+	assert.Equal(t, float64(notAfter.Add(time.Hour).Unix()), getMetric(t, "panoptes_tls_cert_expiry_timestamp_seconds", "test"))
+
+	// the current certificate is kept if the new one is invalid
+	ioutil.WriteFile(certFile, []byte("invalid"), 0644)
+	time.Sleep(200 * time.Millisecond)
+
+	waitSerial(t, tlsConfig, 2)
+	assert.Greater(t, getMetric(t, "panoptes_tls_cert_reload_failures_total", "test"), float64(0))
+
+	// the config is not mutated
+	assert.Equal(t, keyFile, cfg.KeyFile)
+
+	_, _, err = GetTLSServerReloadConfig(ctx, "test", &config.TLSConfig{CertFile: certFile}, tls.NoClientCert)
+	assert.Error(t, err)
+
+	// the metrics are unregistered once it's closed
+	closeFunc()
+	assert.False(t, hasMetric(t, "panoptes_tls_cert_expiry_timestamp_seconds", "test"))
+}
+
+func TestGetTLSServerReloadConfigClientAuth(t *testing.T) {
+	dir, err := ioutil.TempDir("", "panoptes")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	cert, key := genCertKey(t, 1, time.Now().Add(time.Hour))
+	ioutil.WriteFile(certFile, []byte(cert), 0644)
+	ioutil.WriteFile(keyFile, []byte(key), 0644)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := &config.TLSConfig{CertFile: certFile, KeyFile: keyFile, CAFile: certFile}
+	tlsConfig, closeFunc, err := GetTLSServerReloadConfig(ctx, "test.mtls", cfg, tls.VerifyClientCertIfGiven)
+	assert.NoError(t, err)
+
+	clientConfig, err := tlsConfig.GetConfigForClient(nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, clientConfig.ClientCAs)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, clientConfig.ClientAuth)
+	assert.Len(t, clientConfig.Certificates, 1)
+
+	// the client auth requires CA
+	cfg = &config.TLSConfig{CertFile: certFile, KeyFile: keyFile}
+	closeFunc()
+
+	_, _, err = GetTLSServerReloadConfig(ctx, "test.mtls.noca", cfg, tls.VerifyClientCertIfGiven)
+	assert.Error(t, err)
+}
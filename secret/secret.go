@@ -12,6 +12,7 @@ import (
 	"regexp"
 
 	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/secret/file"
 	"github.com/yahoo/panoptes-stream/secret/vault"
 )
 
@@ -25,6 +26,10 @@ func GetSecretEngine(sType string) (Secret, error) {
 	switch sType {
 	case "vault":
 		return vault.New()
+	case "file":
+		return file.New(), nil
+	case "dir":
+		return file.NewDir(), nil
 	}
 
 	return nil, fmt.Errorf("%s secret engine doesn't support", sType)
@@ -56,12 +61,7 @@ func GetTLSServerConfig(cfg *config.TLSConfig) (*tls.Config, error) {
 
 // GetCredentials returns credentials.
 func GetCredentials(sType, path string) (map[string]string, error) {
-	sec, err := GetSecretEngine(sType)
-	if err != nil {
-		return nil, err
-	}
-
-	secrets, err := sec.GetSecrets(path)
+	secrets, err := GetSecrets(sType, path)
 	if err != nil {
 		return nil, err
 	}
@@ -96,12 +96,7 @@ func getTLSConfigRemote(cfg *config.TLSConfig) (*tls.Config, bool, error) {
 		return nil, false, nil
 	}
 
-	sec, err := GetSecretEngine(sType)
-	if err != nil {
-		return nil, ok, err
-	}
-
-	secrets, err := sec.GetSecrets(path)
+	secrets, err := GetSecrets(sType, path)
 	if err != nil {
 		return nil, ok, err
 	}
@@ -4,26 +4,78 @@
 package vault
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/vault/api"
 	"github.com/kelseyhightower/envconfig"
 
 	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/status"
 )
 
+// defaultJWTFile is the Kubernetes service account token file.
+const defaultJWTFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// loginRetryInterval is the interval between the failed logins.
+var loginRetryInterval = 10 * time.Second
+
+// metrics are registered per auth method of the shared client.
+var metrics = map[string]status.Metrics{
+	"loginsTotal":        status.NewCounter("vault_logins_total", "vault successful logins"),
+	"authFailuresTotal":  status.NewCounter("vault_auth_failures_total", "vault login and token renewal failures"),
+	"tokenRenewalsTotal": status.NewCounter("vault_token_renewals_total", "vault token renewals"),
+}
+
+// shared is the Vault client which shared across the secret requests
+// to avoid login per request, it's recreated once the configuration changed.
+var shared struct {
+	sync.Mutex
+	vault *Vault
+}
+
 // Vault represents Hashicorp Vault
 type Vault struct {
 	client *api.Client
+	config *vaultConfig
+
+	mounts map[string]kvMount
+	stop   chan struct{}
+	sync.Mutex
+
+	// loginMu serializes the logins, auth is the last login
+	loginMu sync.Mutex
+	auth    *api.SecretAuth
 }
 
 type vaultConfig struct {
 	Address   string
 	Token     string
 	TLSConfig config.TLSConfig
+
+	// AuthMethod is token (default), approle or kubernetes
+	AuthMethod string
+	AuthPath   string
+	RoleID     string
+	SecretID   string
+	Role       string
+	JWTFile    string
+}
+
+type kvMount struct {
+	path    string
+	version string
 }
 
-// New constructs a new Vault
+// New returns the Vault client, it logs in based on the configured auth method
+// and keeps the token renewed (re-login once the token isn't renewable anymore),
+// the static token is renewed once it's renewable.
 func New() (*Vault, error) {
 	config := &vaultConfig{}
 	prefix := "panoptes_vault"
@@ -32,6 +84,13 @@ func New() (*Vault, error) {
 		return nil, err
 	}
 
+	shared.Lock()
+	defer shared.Unlock()
+
+	if shared.vault != nil && reflect.DeepEqual(shared.vault.config, config) {
+		return shared.vault, nil
+	}
+
 	cfg := api.DefaultConfig()
 
 	if config.Address != "" {
@@ -56,22 +115,327 @@ func New() (*Vault, error) {
 		client.SetToken(config.Token)
 	}
 
-	return &Vault{client: client}, nil
+	v := &Vault{
+		client: client,
+		config: config,
+		mounts: make(map[string]kvMount),
+		stop:   make(chan struct{}),
+	}
+
+	if v.isLoginMethod() {
+		auth, err := v.login()
+		if err != nil {
+			return nil, err
+		}
+
+		go v.keepalive(auth)
+	} else if config.Token != "" {
+		go v.keepaliveToken()
+	}
+
+	if shared.vault != nil {
+		close(shared.vault.stop)
+		status.Unregister(shared.vault.labels(), metrics)
+	}
+
+	status.Register(v.labels(), metrics)
+	shared.vault = v
+
+	return v, nil
 }
 
 // GetSecrets returns all available data as key value for given path
 // it extracts cert and private key from pkcs12 data
 func (v *Vault) GetSecrets(path string) (map[string][]byte, error) {
+	secrets, _, err := v.GetSecretsLease(path)
+	return secrets, err
+}
+
+// GetSecretsLease returns all available data as key value for given path with the lease duration,
+// the KV version 2 paths are translated to the data path e.g. secret/device to secret/data/device.
+func (v *Vault) GetSecretsLease(path string) (map[string][]byte, time.Duration, error) {
+	path, kv2 := v.kvPath(path)
+
+	secrets, err := v.read(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("vault: %v", err)
+	}
+
+	if secrets == nil {
+		return nil, 0, fmt.Errorf("vault: path %s not exist", path)
+	}
+
+	data := secrets.Data
+	if kv2 {
+		data, _ = secrets.Data["data"].(map[string]interface{})
+		if data == nil {
+			return nil, 0, fmt.Errorf("vault: path %s not exist", path)
+		}
+	}
+
+	return getSecrets(data), time.Duration(secrets.LeaseDuration) * time.Second, nil
+}
+
+// read reads the path and logs in once again if the token is not valid anymore.
+func (v *Vault) read(path string) (*api.Secret, error) {
+	token := v.client.Token()
+
 	secrets, err := v.client.Logical().Read(path)
+	if err == nil || !v.isLoginMethod() || !isPermissionDenied(err) {
+		return secrets, err
+	}
+
+	if _, err := v.relogin(token); err != nil {
+		return nil, err
+	}
+
+	return v.client.Logical().Read(path)
+}
+
+// relogin logs in once again unless the token has been already replaced by
+// another login, the concurrent failed requests share a single login.
+func (v *Vault) relogin(token string) (*api.SecretAuth, error) {
+	v.loginMu.Lock()
+	defer v.loginMu.Unlock()
+
+	if v.auth != nil && v.client.Token() != token {
+		return v.auth, nil
+	}
+
+	return v.login()
+}
+
+// login logs in by the auth method and sets the client token,
+// the callers serialize it except the first login.
+func (v *Vault) login() (*api.SecretAuth, error) {
+	var (
+		method = v.config.AuthMethod
+		data   map[string]interface{}
+	)
+
+	switch method {
+	case "approle":
+		data = map[string]interface{}{
+			"role_id":   v.config.RoleID,
+			"secret_id": v.config.SecretID,
+		}
+	case "kubernetes":
+		jwtFile := v.config.JWTFile
+		if jwtFile == "" {
+			jwtFile = defaultJWTFile
+		}
+
+		jwt, err := ioutil.ReadFile(jwtFile)
+		if err != nil {
+			metrics["authFailuresTotal"].Inc()
+			return nil, fmt.Errorf("vault: %v", err)
+		}
+
+		data = map[string]interface{}{
+			"role": v.config.Role,
+			"jwt":  strings.TrimSpace(string(jwt)),
+		}
+	default:
+		return nil, fmt.Errorf("vault: auth method %s not supported", method)
+	}
+
+	authPath := v.config.AuthPath
+	if authPath == "" {
+		authPath = method
+	}
+
+	// the login request must not carry the expired token, it's sent by
+	// a clone as the shared client token is in use by the other requests
+	client, err := v.client.Clone()
 	if err != nil {
 		return nil, fmt.Errorf("vault: %v", err)
 	}
+	client.ClearToken()
 
-	if secrets == nil {
-		return nil, fmt.Errorf("vault: path %s not exist", path)
+	secret, err := client.Logical().Write(fmt.Sprintf("auth/%s/login", strings.Trim(authPath, "/")), data)
+	if err != nil {
+		metrics["authFailuresTotal"].Inc()
+		return nil, fmt.Errorf("vault: %s login: %v", method, err)
+	}
+
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		metrics["authFailuresTotal"].Inc()
+		return nil, fmt.Errorf("vault: %s login: token not available", method)
+	}
+
+	v.client.SetToken(secret.Auth.ClientToken)
+	v.auth = secret.Auth
+	metrics["loginsTotal"].Inc()
+
+	return secret.Auth, nil
+}
+
+// keepalive renews the token at two-thirds of its lease duration, it logs in
+// once again if the token can not be renewed. the static token renewal is
+// retried as it can not be obtained once again.
+func (v *Vault) keepalive(auth *api.SecretAuth) {
+	for {
+		// the token doesn't expire or the static token isn't renewable
+		if auth.LeaseDuration <= 0 || (!auth.Renewable && !v.isLoginMethod()) {
+			return
+		}
+
+		select {
+		case <-time.After(time.Duration(auth.LeaseDuration) * time.Second * 2 / 3):
+		case <-v.stop:
+			return
+		}
+
+		for {
+			a, err := v.refresh(auth)
+			if err == nil {
+				auth = a
+				break
+			}
+
+			select {
+			case <-time.After(loginRetryInterval):
+			case <-v.stop:
+				return
+			}
+		}
+	}
+}
+
+// keepaliveToken looks up the static token lease and keeps it renewed.
+func (v *Vault) keepaliveToken() {
+	for {
+		auth, err := v.lookupToken()
+		if err == nil {
+			v.keepalive(auth)
+			return
+		}
+
+		metrics["authFailuresTotal"].Inc()
+
+		select {
+		case <-time.After(loginRetryInterval):
+		case <-v.stop:
+			return
+		}
+	}
+}
+
+// refresh renews the token or logs in once again.
+func (v *Vault) refresh(auth *api.SecretAuth) (*api.SecretAuth, error) {
+	if auth.Renewable {
+		// the static token is renewed by its own TTL
+		increment := auth.LeaseDuration
+		if !v.isLoginMethod() {
+			increment = 0
+		}
+
+		secret, err := v.client.Auth().Token().RenewSelf(increment)
+		if err == nil && secret != nil && secret.Auth != nil {
+			metrics["tokenRenewalsTotal"].Inc()
+			return secret.Auth, nil
+		}
+
+		metrics["authFailuresTotal"].Inc()
+
+		if !v.isLoginMethod() {
+			return nil, fmt.Errorf("vault: token renewal: %v", err)
+		}
+	}
+
+	return v.relogin(auth.ClientToken)
+}
+
+func (v *Vault) lookupToken() (*api.SecretAuth, error) {
+	secret, err := v.client.Auth().Token().LookupSelf()
+	if err != nil {
+		return nil, err
+	}
+
+	ttl, err := secret.TokenTTL()
+	if err != nil {
+		return nil, err
+	}
+
+	renewable, err := secret.TokenIsRenewable()
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.SecretAuth{
+		ClientToken:   v.client.Token(),
+		LeaseDuration: int(ttl.Seconds()),
+		Renewable:     renewable,
+	}, nil
+}
+
+// kvPath returns the data path and true if the path belongs to a KV version 2 mount,
+// the mount is checked once per path and it's considered version 1 if it's not available.
+func (v *Vault) kvPath(path string) (string, bool) {
+	path = strings.TrimPrefix(path, "/")
+
+	v.Lock()
+	if v.mounts == nil {
+		v.mounts = make(map[string]kvMount)
+	}
+	mount, ok := v.mounts[path]
+	v.Unlock()
+
+	if !ok {
+		mount = v.getMount(path)
+
+		v.Lock()
+		v.mounts[path] = mount
+		v.Unlock()
+	}
+
+	if mount.version != "2" {
+		return path, false
+	}
+
+	rest := strings.TrimPrefix(path, mount.path)
+	if strings.HasPrefix(rest, "data/") {
+		return path, true
+	}
+
+	return mount.path + "data/" + rest, true
+}
+
+func (v *Vault) getMount(path string) kvMount {
+	secret, err := v.read("sys/internal/ui/mounts/" + path)
+	if err != nil || secret == nil {
+		return kvMount{}
+	}
+
+	mount := kvMount{}
+	mount.path, _ = secret.Data["path"].(string)
+	if options, ok := secret.Data["options"].(map[string]interface{}); ok {
+		mount.version, _ = options["version"].(string)
+	}
+
+	return mount
+}
+
+func (v *Vault) labels() status.Labels {
+	method := v.config.AuthMethod
+	if method == "" {
+		method = "token"
+	}
+
+	return status.Labels{"method": method}
+}
+
+func (v *Vault) isLoginMethod() bool {
+	return v.config != nil && v.config.AuthMethod != "" && v.config.AuthMethod != "token"
+}
+
+func isPermissionDenied(err error) bool {
+	var respErr *api.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.StatusCode == http.StatusForbidden
 	}
 
-	return getSecrets(secrets.Data), nil
+	return false
 }
 
 // getSecrets returns private key and certificate encoded as PEM
@@ -79,7 +443,12 @@ func getSecrets(data map[string]interface{}) map[string][]byte {
 	var result = make(map[string][]byte)
 
 	for key, value := range data {
-		result[key] = []byte(value.(string))
+		switch v := value.(type) {
+		case string:
+			result[key] = []byte(v)
+		default:
+			result[key] = []byte(fmt.Sprint(v))
+		}
 	}
 
 	return result
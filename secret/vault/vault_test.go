@@ -4,12 +4,20 @@
 package vault
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	kv "github.com/hashicorp/vault-plugin-secrets-kv"
 	"github.com/hashicorp/vault/api"
-	"github.com/hashicorp/vault/http"
+	vaulthttp "github.com/hashicorp/vault/http"
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/hashicorp/vault/vault"
 	"github.com/stretchr/testify/assert"
@@ -24,7 +32,7 @@ func createVaultTestCluster(t *testing.T) *vault.TestCluster {
 		},
 	}
 	cluster := vault.NewTestCluster(t, coreConfig, &vault.TestClusterOptions{
-		HandlerFunc: http.Handler,
+		HandlerFunc: vaulthttp.Handler,
 		NumCores:    1,
 		Logger:      nil,
 	})
@@ -54,7 +62,7 @@ func TestGetSecrets(t *testing.T) {
 
 	client.Logical().Write(path, data)
 
-	v := &Vault{client}
+	v := &Vault{client: client}
 	r, err := v.GetSecrets(path)
 	if err != nil {
 		t.Fatal(err)
@@ -70,6 +78,30 @@ func TestGetSecrets(t *testing.T) {
 	}
 }
 
+func TestGetSecretsLease(t *testing.T) {
+	cluster := createVaultTestCluster(t)
+	defer cluster.Cleanup()
+
+	client := cluster.Cores[0].Client
+
+	path := "secrets/v1/device"
+	data := map[string]interface{}{"admin": "topsecret", "ttl": "30s"}
+
+	client.Logical().Write(path, data)
+
+	v := &Vault{client: client}
+	r, lease, err := v.GetSecretsLease(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "topsecret", string(r["admin"]))
+	assert.Equal(t, 30*time.Second, lease)
+
+	_, _, err = v.GetSecretsLease("secrets/v1/notexist")
+	assert.Error(t, err)
+}
+
 func TestNew(t *testing.T) {
 	os.Setenv("PANOPTES_VAULT_TLSCONFIG_ENABLED", "true")
 	os.Setenv("PANOPTES_VAULT_TLSCONFIG_INSECURESKIPVERIFY", "true")
@@ -80,3 +112,230 @@ func TestNew(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, v)
 }
+
+// vaultStandIn represents a Vault API stand-in for the auth methods and KV version 2.
+type vaultStandIn struct {
+	sync.Mutex
+	token    string
+	logins   int
+	renewals int
+}
+
+func (s *vaultStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	login := func() {
+		s.logins++
+		s.token = fmt.Sprintf("token-%d", s.logins)
+		fmt.Fprintf(w, `{"auth":{"client_token":"%s","lease_duration":1,"renewable":true}}`, s.token)
+	}
+
+	switch r.URL.Path {
+	case "/v1/auth/approle/login":
+		if body["role_id"] != "panoptes" || body["secret_id"] != "topsecret" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errors":["invalid role or secret ID"]}`)
+			return
+		}
+		login()
+		return
+	case "/v1/auth/k8s/login":
+		if body["role"] != "panoptes" || body["jwt"] != "jwt-token" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"errors":["permission denied"]}`)
+			return
+		}
+		login()
+		return
+	}
+
+	if r.Header.Get("X-Vault-Token") != s.token {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"errors":["permission denied"]}`)
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/v1/auth/token/lookup-self":
+		fmt.Fprintf(w, `{"data":{"id":"%s","ttl":1,"renewable":true}}`, s.token)
+	case r.URL.Path == "/v1/auth/token/renew-self":
+		s.renewals++
+		fmt.Fprintf(w, `{"auth":{"client_token":"%s","lease_duration":1,"renewable":true}}`, s.token)
+	case strings.HasPrefix(r.URL.Path, "/v1/sys/internal/ui/mounts/kv/"):
+		fmt.Fprint(w, `{"data":{"path":"kv/","type":"kv","options":{"version":"2"}}}`)
+	case r.URL.Path == "/v1/kv/data/device":
+		fmt.Fprint(w, `{"data":{"data":{"admin":"topsecret"},"metadata":{"version":3}}}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errors":[]}`)
+	}
+}
+
+func (s *vaultStandIn) expire() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.token = "expired"
+}
+
+func (s *vaultStandIn) get() (int, int) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.logins, s.renewals
+}
+
+func setVaultEnv(t *testing.T, env map[string]string) {
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+
+	t.Cleanup(func() {
+		for k := range env {
+			os.Unsetenv(k)
+		}
+	})
+}
+
+func TestAppRoleAuth(t *testing.T) {
+	standIn := &vaultStandIn{}
+	ts := httptest.NewServer(standIn)
+	defer ts.Close()
+
+	setVaultEnv(t, map[string]string{
+		"PANOPTES_VAULT_ADDRESS":    ts.URL,
+		"PANOPTES_VAULT_TOKEN":      "",
+		"PANOPTES_VAULT_AUTHMETHOD": "approle",
+		"PANOPTES_VAULT_ROLEID":     "panoptes",
+		"PANOPTES_VAULT_SECRETID":   "topsecret",
+	})
+
+	v, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// shared client
+	v2, err := New()
+	assert.NoError(t, err)
+	assert.Equal(t, v, v2)
+
+	// kv version 2
+	secrets, err := v.GetSecrets("kv/device")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"admin": []byte("topsecret")}, secrets)
+
+	secrets, err = v.GetSecrets("kv/data/device")
+	assert.NoError(t, err)
+	assert.Equal(t, "topsecret", string(secrets["admin"]))
+
+	// token renewal
+	time.Sleep(1500 * time.Millisecond)
+	logins, renewals := standIn.get()
+	assert.Equal(t, 1, logins)
+	assert.GreaterOrEqual(t, renewals, 1)
+
+	// re-login once the token is not valid anymore
+	standIn.expire()
+
+	secrets, err = v.GetSecrets("kv/device")
+	assert.NoError(t, err)
+	assert.Equal(t, "topsecret", string(secrets["admin"]))
+
+	logins, _ = standIn.get()
+	assert.GreaterOrEqual(t, logins, 2)
+
+	// concurrent requests share the re-login
+	standIn.expire()
+	logins, _ = standIn.get()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := v.GetSecrets("kv/device")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	n, _ := standIn.get()
+	assert.Equal(t, logins+1, n)
+
+	// login failure
+	failures := metrics["authFailuresTotal"].Get()
+	os.Setenv("PANOPTES_VAULT_SECRETID", "wrong")
+
+	_, err = New()
+	assert.Error(t, err)
+	assert.Greater(t, metrics["authFailuresTotal"].Get(), failures)
+}
+
+func TestStaticTokenRenewal(t *testing.T) {
+	standIn := &vaultStandIn{token: "static"}
+	ts := httptest.NewServer(standIn)
+	defer ts.Close()
+
+	setVaultEnv(t, map[string]string{
+		"PANOPTES_VAULT_ADDRESS":    ts.URL,
+		"PANOPTES_VAULT_TOKEN":      "static",
+		"PANOPTES_VAULT_AUTHMETHOD": "",
+	})
+
+	v, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+	logins, renewals := standIn.get()
+	assert.Equal(t, 0, logins)
+	assert.GreaterOrEqual(t, renewals, 1)
+	assert.Equal(t, "static", v.client.Token())
+}
+
+func TestKubernetesAuth(t *testing.T) {
+	standIn := &vaultStandIn{}
+	ts := httptest.NewServer(standIn)
+	defer ts.Close()
+
+	jwtFile, err := ioutil.TempFile("", "jwt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(jwtFile.Name())
+
+	jwtFile.WriteString("jwt-token\n")
+	jwtFile.Close()
+
+	setVaultEnv(t, map[string]string{
+		"PANOPTES_VAULT_ADDRESS":    ts.URL,
+		"PANOPTES_VAULT_TOKEN":      "",
+		"PANOPTES_VAULT_AUTHMETHOD": "kubernetes",
+		"PANOPTES_VAULT_AUTHPATH":   "k8s",
+		"PANOPTES_VAULT_ROLE":       "panoptes",
+		"PANOPTES_VAULT_JWTFILE":    jwtFile.Name(),
+	})
+
+	v, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secrets, err := v.GetSecrets("kv/device")
+	assert.NoError(t, err)
+	assert.Equal(t, "topsecret", string(secrets["admin"]))
+
+	os.Setenv("PANOPTES_VAULT_JWTFILE", "/not/exist")
+	_, err = New()
+	assert.Error(t, err)
+
+	os.Setenv("PANOPTES_VAULT_AUTHMETHOD", "ldap")
+	_, err = New()
+	assert.Error(t, err)
+}
@@ -0,0 +1,88 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package status
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// capabilities holds the devices capabilities e.g. gNMI version,
+// encodings and models per device host and telemetry service.
+var capabilities = struct {
+	devices map[string]map[string]interface{}
+	sync.RWMutex
+}{
+	devices: make(map[string]map[string]interface{}),
+}
+
+type capabilitiesHandler struct{}
+
+// ServeHTTP returns the capabilities of all devices or the requested device
+// (host query parameter) per service or the requested service (service query parameter) as JSON.
+func (c *capabilitiesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	capabilities.RLock()
+	defer capabilities.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if host := r.URL.Query().Get("host"); host != "" {
+		services, ok := capabilities.devices[host]
+		if !ok {
+			http.Error(w, "device not found", http.StatusNotFound)
+			return
+		}
+
+		if service := r.URL.Query().Get("service"); service != "" {
+			v, ok := services[service]
+			if !ok {
+				http.Error(w, "service not found", http.StatusNotFound)
+				return
+			}
+
+			json.NewEncoder(w).Encode(v)
+			return
+		}
+
+		json.NewEncoder(w).Encode(services)
+		return
+	}
+
+	json.NewEncoder(w).Encode(capabilities.devices)
+}
+
+// SetCapabilities sets the device capabilities of a service.
+func SetCapabilities(host, service string, v interface{}) {
+	capabilities.Lock()
+	defer capabilities.Unlock()
+
+	if _, ok := capabilities.devices[host]; !ok {
+		capabilities.devices[host] = make(map[string]interface{})
+	}
+
+	capabilities.devices[host][service] = v
+}
+
+// GetCapabilities returns the device capabilities of a service.
+func GetCapabilities(host, service string) (interface{}, bool) {
+	capabilities.RLock()
+	defer capabilities.RUnlock()
+
+	v, ok := capabilities.devices[host][service]
+
+	return v, ok
+}
+
+// DeleteCapabilities deletes the device capabilities of a service.
+func DeleteCapabilities(host, service string) {
+	capabilities.Lock()
+	defer capabilities.Unlock()
+
+	delete(capabilities.devices[host], service)
+
+	if len(capabilities.devices[host]) < 1 {
+		delete(capabilities.devices, host)
+	}
+}
@@ -4,6 +4,7 @@
 package status
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"sync/atomic"
@@ -13,7 +14,6 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/yahoo/panoptes-stream/config"
-	"github.com/yahoo/panoptes-stream/secret"
 )
 
 // Metrics represents counter and gauge metrics.
@@ -26,10 +26,15 @@ type Metrics interface {
 
 // Status represents Panoptes status and healthcheck
 type Status struct {
-	cfg    config.Config
-	logger *zap.Logger
+	cfg          config.Config
+	logger       *zap.Logger
+	getTLSConfig TLSConfigFunc
 }
 
+// TLSConfigFunc returns the status server TLS config, it's provided by
+// the caller as the secrets depend on the status metrics.
+type TLSConfigFunc func(*config.TLSConfig) (*tls.Config, error)
+
 // Metric represents a metric
 type Metric struct {
 	Name string
@@ -58,10 +63,11 @@ func (h *healthcheck) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 // New constructs a new status
-func New(cfg config.Config) *Status {
+func New(cfg config.Config, getTLSConfig TLSConfigFunc) *Status {
 	return &Status{
-		cfg:    cfg,
-		logger: cfg.Logger(),
+		cfg:          cfg,
+		logger:       cfg.Logger(),
+		getTLSConfig: getTLSConfig,
 	}
 }
 
@@ -86,12 +92,13 @@ func (s *Status) start() error {
 
 	http.Handle("/metrics", promhttp.Handler())
 	http.Handle("/healthcheck", new(healthcheck))
+	http.Handle("/capabilities", new(capabilitiesHandler))
 
 	if !config.TLSConfig.Enabled {
 		return http.ListenAndServe(config.Addr, nil)
 	}
 
-	tlsConfig, err := secret.GetTLSServerConfig(&config.TLSConfig)
+	tlsConfig, err := s.getTLSConfig(&config.TLSConfig)
 	if err != nil {
 		return err
 	}
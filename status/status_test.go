@@ -114,7 +114,7 @@ func TestStart(t *testing.T) {
 		},
 	}
 
-	s := New(cfg)
+	s := New(cfg, nil)
 	s.Start()
 	time.Sleep(time.Millisecond * 500)
 
@@ -131,3 +131,54 @@ func TestStart(t *testing.T) {
 	defer resp.Body.Close()
 	assert.Equal(t, 200, resp.StatusCode)
 }
+
+func TestCapabilitiesHandler(t *testing.T) {
+	SetCapabilities("127.0.0.1", "gnmi", map[string]string{"version": "0.7.0"})
+	SetCapabilities("127.0.0.1", "arista.gnmi", map[string]string{"version": "0.6.0"})
+	defer DeleteCapabilities("127.0.0.1", "gnmi")
+	defer DeleteCapabilities("127.0.0.1", "arista.gnmi")
+
+	ts := httptest.NewServer(new(capabilitiesHandler))
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "?host=127.0.0.1&service=gnmi")
+	assert.NoError(t, err)
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"version": "0.7.0"}`, string(body))
+
+	res, err = http.Get(ts.URL + "?host=127.0.0.1")
+	assert.NoError(t, err)
+	body, err = ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"gnmi": {"version": "0.7.0"}, "arista.gnmi": {"version": "0.6.0"}}`, string(body))
+
+	res, err = http.Get(ts.URL + "?host=127.0.0.2")
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	res, err = http.Get(ts.URL + "?host=127.0.0.1&service=cisco.gnmi")
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	res, err = http.Get(ts.URL)
+	assert.NoError(t, err)
+	body, err = ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"127.0.0.1": {"gnmi": {"version": "0.7.0"}, "arista.gnmi": {"version": "0.6.0"}}}`, string(body))
+
+	// the services don't overwrite each other
+	DeleteCapabilities("127.0.0.1", "arista.gnmi")
+	v, ok := GetCapabilities("127.0.0.1", "gnmi")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"version": "0.7.0"}, v)
+
+	DeleteCapabilities("127.0.0.1", "gnmi")
+	_, ok = GetCapabilities("127.0.0.1", "gnmi")
+	assert.False(t, ok)
+}
@@ -27,9 +27,9 @@ var gnmiVersion = "0.0.1"
 // GNMI represents a gNMI for Arista EOS telemetry.
 type GNMI struct {
 	conn          *grpc.ClientConn
-	subscriptions []*gpb.Subscription
+	subscriptions []*telemetry.GNMISubscriptionGroup
 
-	dataChan chan *gpb.SubscribeResponse
+	dataChan chan telemetry.GNMIResponse
 	outChan  telemetry.ExtDSChan
 	logger   *zap.Logger
 
@@ -53,10 +53,10 @@ func New(logger *zap.Logger, conn *grpc.ClientConn, sensors []*config.Sensor, ou
 	return &GNMI{
 		logger:        logger,
 		conn:          conn,
-		subscriptions: telemetry.GetGNMISubscriptions(sensors),
+		subscriptions: telemetry.GetGNMISubscriptionGroups(sensors),
 		pathOutput:    telemetry.GetPathOutput(sensors),
 		defaultOutput: telemetry.GetDefaultOutput(sensors),
-		dataChan:      make(chan *gpb.SubscribeResponse, 100),
+		dataChan:      make(chan telemetry.GNMIResponse, 100),
 		outChan:       outChan,
 		metrics:       metrics,
 	}
@@ -66,47 +66,19 @@ func New(logger *zap.Logger, conn *grpc.ClientConn, sensors []*config.Sensor, ou
 func (g *GNMI) Start(ctx context.Context) error {
 	defer status.Unregister(status.Labels{"host": g.conn.Target()}, g.metrics)
 
-	client := gpb.NewGNMIClient(g.conn)
-	subReq := &gpb.SubscribeRequest{
-		Request: &gpb.SubscribeRequest_Subscribe{
-			Subscribe: &gpb.SubscriptionList{
-				Mode:         gpb.SubscriptionList_STREAM,
-				Encoding:     gpb.Encoding(gpb.Encoding_value["PROTO"]),
-				Subscription: g.subscriptions,
-				UpdatesOnly:  false,
-			},
-		},
-	}
-
-	subClient, err := client.Subscribe(ctx)
-	if err != nil {
-		return err
-	}
-
-	err = subClient.Send(subReq)
-	if err != nil {
-		return err
-	}
-
 	workers := config.GetEnvInt("ARISTA_GNMI_WORKERS", 1)
 	for i := 0; i < workers; i++ {
 		go g.worker(ctx)
 	}
 
-	for {
-		resp, err := subClient.Recv()
-		if err != nil && ctx.Err() == nil {
-			return err
-		}
-
-		if ctx.Err() != nil {
-			return nil
-		}
+	client := gpb.NewGNMIClient(g.conn)
 
-		g.dataChan <- resp
-		g.metrics["gRPCDataTotal"].Inc()
+	encoding, subscriptions, err := telemetry.GNMICapabilitiesHandshake(ctx, client, g.conn.Target(), "arista.gnmi", gpb.Encoding(gpb.Encoding_value["PROTO"]), g.subscriptions, g.logger)
+	if err != nil {
+		return err
 	}
 
+	return telemetry.GNMISubscribe(ctx, client, encoding, subscriptions, g.dataChan, g.metrics["gRPCDataTotal"])
 }
 func (g *GNMI) worker(ctx context.Context) {
 	var (
@@ -124,6 +96,14 @@ func (g *GNMI) worker(ctx context.Context) {
 
 			start = time.Now()
 
+			if d.GetSyncResponse() {
+				if err := telemetry.SyncResponse(g.outChan, g.pathOutput, d.Group.Paths, systemID); err != nil {
+					g.metrics["dropsTotal"].Inc()
+					g.logger.Error("arista.gnmi", zap.Error(err))
+				}
+				continue
+			}
+
 			resp, ok := d.Response.(*gpb.SubscribeResponse_Update)
 			if !ok {
 				continue
@@ -136,6 +116,13 @@ func (g *GNMI) worker(ctx context.Context) {
 				}
 			}
 
+			for _, path := range resp.Update.Delete {
+				err := g.datastore(buf, resp.Update, &gpb.Update{Path: path}, systemID)
+				if err != nil {
+					g.logger.Error("arista.gnmi", zap.Error(err))
+				}
+			}
+
 			g.metrics["processNSecond"].Set(uint64(time.Since(start).Nanoseconds()))
 
 		case <-ctx.Done():
@@ -207,9 +194,14 @@ func (g *GNMI) datastore(buf *bytes.Buffer, n *gpb.Notification, update *gpb.Upd
 		return errors.New("output not found")
 	}
 
-	value, err := getValue(update.Val)
-	if err != nil {
-		return err
+	// the deletes are updates without value
+	var value interface{}
+	if update.Val != nil {
+		var err error
+		value, err = getValue(update.Val)
+		if err != nil {
+			return err
+		}
 	}
 
 	ds := telemetry.DataStore{
@@ -221,6 +213,10 @@ func (g *GNMI) datastore(buf *bytes.Buffer, n *gpb.Notification, update *gpb.Upd
 		"value":     value,
 	}
 
+	if update.Val == nil {
+		ds["operation"] = telemetry.OperationDelete
+	}
+
 	select {
 	case g.outChan <- telemetry.ExtDataStore{
 		DS:     ds,
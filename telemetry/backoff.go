@@ -0,0 +1,112 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package telemetry
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/yahoo/panoptes-stream/config"
+)
+
+// default backoff policy (unit is second except multiplier and jitter)
+const (
+	defaultBackoffInitial     = 30
+	defaultBackoffMax         = 300
+	defaultBackoffMultiplier  = 1.5
+	defaultBackoffJitter      = 0.5
+	defaultBackoffResetWindow = 1800
+)
+
+type backoff struct {
+	initial     time.Duration
+	max         time.Duration
+	multiplier  float64
+	jitter      float64
+	resetWindow time.Duration
+
+	d    time.Duration
+	last time.Time
+	rand *rand.Rand
+}
+
+// newBackoff returns a backoff per the policy, the options
+// which they're not configured set to the default values.
+func newBackoff(policy config.Backoff) *backoff {
+	b := &backoff{
+		initial:     time.Duration(policy.Initial) * time.Second,
+		max:         time.Duration(policy.Max) * time.Second,
+		multiplier:  policy.Multiplier,
+		jitter:      defaultBackoffJitter,
+		resetWindow: time.Duration(policy.ResetWindow) * time.Second,
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	if b.initial <= 0 {
+		b.initial = defaultBackoffInitial * time.Second
+	}
+
+	if b.max <= 0 {
+		b.max = defaultBackoffMax * time.Second
+	}
+
+	if b.max < b.initial {
+		b.max = b.initial
+	}
+
+	if b.multiplier < 1 {
+		b.multiplier = defaultBackoffMultiplier
+	}
+
+	// the jitter can be zero, it's the default once it's not configured
+	if policy.Jitter != nil && *policy.Jitter >= 0 && *policy.Jitter <= 1 {
+		b.jitter = *policy.Jitter
+	}
+
+	if b.resetWindow <= 0 {
+		b.resetWindow = defaultBackoffResetWindow * time.Second
+	}
+
+	return b
+}
+
+func (b *backoff) reset() {
+	b.last = time.Now()
+	b.d = b.initial
+}
+
+// next returns the duration to wait before the next connection attempt, the first
+// attempt isn't delayed and the delay goes back to the initial once the last attempt
+// is older than the reset window (the connection was stable).
+func (b *backoff) next() time.Duration {
+	// first call - bypass backoff
+	if b.d == 0 {
+		b.reset()
+		return 0
+	}
+
+	// reset back off
+	if time.Since(b.last) > b.resetWindow {
+		b.reset()
+		return b.withJitter(b.d)
+	}
+
+	d := b.d
+
+	b.d = time.Duration(float64(b.d) * b.multiplier)
+	if b.d > b.max {
+		b.d = b.max
+	}
+
+	b.last = time.Now()
+
+	return b.withJitter(d)
+}
+
+// withJitter spreads the duration randomly in [d-d*jitter, d+d*jitter].
+func (b *backoff) withJitter(d time.Duration) time.Duration {
+	delta := float64(d) * b.jitter
+
+	return time.Duration(float64(d) - delta + b.rand.Float64()*2*delta)
+}
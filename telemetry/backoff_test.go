@@ -0,0 +1,52 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package telemetry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yahoo/panoptes-stream/config"
+)
+
+func TestBackoffDefaults(t *testing.T) {
+	b := newBackoff(config.Backoff{})
+
+	assert.Equal(t, defaultBackoffInitial*time.Second, b.initial)
+	assert.Equal(t, defaultBackoffMax*time.Second, b.max)
+	assert.Equal(t, defaultBackoffMultiplier, b.multiplier)
+	assert.Equal(t, defaultBackoffJitter, b.jitter)
+	assert.Equal(t, defaultBackoffResetWindow*time.Second, b.resetWindow)
+}
+
+func TestBackoffNoJitter(t *testing.T) {
+	jitter := 0.0
+	b := newBackoff(config.Backoff{Initial: 10, Jitter: &jitter})
+	assert.Equal(t, 0.0, b.jitter)
+
+	b.next()
+	assert.Equal(t, 10*time.Second, b.next())
+}
+
+func TestBackoffNext(t *testing.T) {
+	jitter := 0.1
+	b := newBackoff(config.Backoff{Initial: 10, Max: 30, Multiplier: 2, Jitter: &jitter, ResetWindow: 60})
+
+	assert.Equal(t, time.Duration(0), b.next())
+
+	expected := []time.Duration{10, 20, 30, 30}
+	for _, e := range expected {
+		d := b.next()
+		assert.GreaterOrEqual(t, int64(d), int64(e*time.Second*9/10))
+		assert.LessOrEqual(t, int64(d), int64(e*time.Second*11/10))
+	}
+
+	// reset window
+	b.last = time.Now().Add(-2 * time.Minute)
+	d := b.next()
+	assert.LessOrEqual(t, int64(d), int64(11*time.Second))
+	assert.Equal(t, 10*time.Second, b.d)
+}
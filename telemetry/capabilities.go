@@ -0,0 +1,147 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/ygot/ygot"
+	"go.uber.org/zap"
+
+	"github.com/yahoo/panoptes-stream/status"
+)
+
+// fallbackEncodings are the alternative encodings once the requested encoding is not supported.
+var fallbackEncodings = []gpb.Encoding{gpb.Encoding_PROTO, gpb.Encoding_JSON_IETF, gpb.Encoding_JSON}
+
+// GNMICapabilities represents the gNMI capabilities of a device and the validation result.
+type GNMICapabilities struct {
+	Version   string            `json:"version"`
+	Encodings []string          `json:"encodings"`
+	Models    []string          `json:"models"`
+	Encoding  string            `json:"encoding"`
+	Rejected  map[string]string `json:"rejected,omitempty"`
+}
+
+// GNMICapabilitiesHandshake requests the device capabilities before subscribing, it records them
+// at the status server per host and service and returns the supported encoding and the subscription groups without
+// the sensors which their models are not supported. the devices which they don't support the
+// capabilities RPC are subscribed as requested.
+func GNMICapabilitiesHandshake(ctx context.Context, client gpb.GNMIClient, target, service string, encoding gpb.Encoding,
+	groups []*GNMISubscriptionGroup, logger *zap.Logger) (gpb.Encoding, []*GNMISubscriptionGroup, error) {
+	host, _, err := net.SplitHostPort(target)
+	if err != nil {
+		host = target
+	}
+
+	resp, err := client.Capabilities(ctx, &gpb.CapabilityRequest{})
+	if err != nil {
+		logger.Warn("capabilities", zap.String("host", host), zap.Error(err))
+		return encoding, groups, nil
+	}
+
+	caps, encoding, groups, err := ValidateGNMICapabilities(resp, encoding, groups)
+	status.SetCapabilities(host, service, caps)
+
+	for path, reason := range caps.Rejected {
+		logger.Error("capabilities", zap.String("host", host), zap.String("path", path), zap.String("error", reason))
+	}
+
+	return encoding, groups, err
+}
+
+// ValidateGNMICapabilities adapts the encoding to a supported encoding and rejects the sensors
+// which their models are not supported (the path elements with module name e.g. openconfig-interfaces:interfaces).
+func ValidateGNMICapabilities(resp *gpb.CapabilityResponse, encoding gpb.Encoding, groups []*GNMISubscriptionGroup) (*GNMICapabilities, gpb.Encoding, []*GNMISubscriptionGroup, error) {
+	var (
+		caps = &GNMICapabilities{
+			Version:  resp.GNMIVersion,
+			Rejected: make(map[string]string),
+		}
+		encodings  = make(map[gpb.Encoding]bool)
+		models     = make(map[string]bool)
+		validation []*GNMISubscriptionGroup
+	)
+
+	for _, e := range resp.SupportedEncodings {
+		encodings[e] = true
+		caps.Encodings = append(caps.Encodings, e.String())
+	}
+
+	for _, m := range resp.SupportedModels {
+		models[m.Name] = true
+		caps.Models = append(caps.Models, fmt.Sprintf("%s@%s", m.Name, m.Version))
+	}
+
+	if len(encodings) > 0 && !encodings[encoding] {
+		supported := false
+		for _, e := range fallbackEncodings {
+			if encodings[e] {
+				encoding, supported = e, true
+				break
+			}
+		}
+
+		if !supported {
+			caps.Encoding = encoding.String()
+			return caps, encoding, nil, fmt.Errorf("encoding %s not supported", encoding)
+		}
+	}
+
+	caps.Encoding = encoding.String()
+
+	for _, group := range groups {
+		var (
+			subscriptions []*gpb.Subscription
+			paths         []string
+		)
+
+		for i, sub := range group.Subscriptions {
+			if module, ok := unsupportedModel(sub.Path, models); ok {
+				path, _ := ygot.PathToString(sub.Path)
+				caps.Rejected[path] = fmt.Sprintf("model %s not supported", module)
+				continue
+			}
+
+			subscriptions = append(subscriptions, sub)
+			if i < len(group.Paths) {
+				paths = append(paths, group.Paths[i])
+			}
+		}
+
+		if len(subscriptions) > 0 {
+			validation = append(validation, &GNMISubscriptionGroup{
+				Mode:          group.Mode,
+				Interval:      group.Interval,
+				Subscriptions: subscriptions,
+				Paths:         paths,
+			})
+		}
+	}
+
+	if len(validation) < 1 {
+		return caps, encoding, nil, errors.New("sensors not supported")
+	}
+
+	return caps, encoding, validation, nil
+}
+
+func unsupportedModel(path *gpb.Path, models map[string]bool) (string, bool) {
+	if len(models) < 1 || path == nil {
+		return "", false
+	}
+
+	for _, elem := range path.Elem {
+		if i := strings.Index(elem.Name, ":"); i > -1 && !models[elem.Name[:i]] {
+			return elem.Name[:i], true
+		}
+	}
+
+	return "", false
+}
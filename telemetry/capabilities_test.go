@@ -0,0 +1,91 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/status"
+	"github.com/yahoo/panoptes-stream/telemetry/mock"
+)
+
+func capabilityResponse() *gpb.CapabilityResponse {
+	return &gpb.CapabilityResponse{
+		GNMIVersion:        "0.7.0",
+		SupportedEncodings: []gpb.Encoding{gpb.Encoding_JSON, gpb.Encoding_JSON_IETF},
+		SupportedModels: []*gpb.ModelData{
+			{Name: "openconfig-interfaces", Organization: "OpenConfig working group", Version: "2.4.3"},
+		},
+	}
+}
+
+func TestValidateGNMICapabilities(t *testing.T) {
+	groups := GetGNMISubscriptionGroups([]*config.Sensor{
+		{Path: "/openconfig-interfaces:interfaces/interface"},
+		{Path: "/openconfig-network-instance:network-instances/network-instance"},
+		{Path: "/components/component", ListMode: "once"},
+	})
+
+	caps, encoding, groups, err := ValidateGNMICapabilities(capabilityResponse(), gpb.Encoding_PROTO, groups)
+	assert.NoError(t, err)
+	assert.Equal(t, gpb.Encoding_JSON_IETF, encoding)
+	assert.Len(t, groups, 2)
+	assert.Len(t, groups[0].Subscriptions, 1)
+	assert.Equal(t, []string{"/openconfig-interfaces:interfaces/interface"}, groups[0].Paths)
+	assert.Equal(t, "0.7.0", caps.Version)
+	assert.Equal(t, "JSON_IETF", caps.Encoding)
+	assert.Equal(t, []string{"openconfig-interfaces@2.4.3"}, caps.Models)
+	assert.Equal(t, map[string]string{
+		"/openconfig-network-instance:network-instances/network-instance": "model openconfig-network-instance not supported",
+	}, caps.Rejected)
+
+	// all sensors rejected
+	groups = GetGNMISubscriptionGroups([]*config.Sensor{{Path: "/openconfig-system:system"}})
+	_, _, _, err = ValidateGNMICapabilities(capabilityResponse(), gpb.Encoding_JSON, groups)
+	assert.Error(t, err)
+
+	// encoding not supported
+	resp := capabilityResponse()
+	resp.SupportedEncodings = []gpb.Encoding{gpb.Encoding_ASCII}
+	_, _, _, err = ValidateGNMICapabilities(resp, gpb.Encoding_PROTO, groups)
+	assert.Error(t, err)
+}
+
+func TestGNMICapabilitiesHandshake(t *testing.T) {
+	addr := "127.0.0.1:50059"
+
+	ln, err := mock.StartGNMICapServer(addr, mock.Update{}, capabilityResponse())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	groups := GetGNMISubscriptionGroups([]*config.Sensor{{Path: "/interfaces/interface"}})
+
+	encoding, groups, err := GNMICapabilitiesHandshake(ctx, gpb.NewGNMIClient(conn), addr, "gnmi", gpb.Encoding_JSON, groups, zap.NewNop())
+	assert.NoError(t, err)
+	assert.Equal(t, gpb.Encoding_JSON, encoding)
+	assert.Len(t, groups, 1)
+
+	caps, ok := status.GetCapabilities("127.0.0.1", "gnmi")
+	assert.True(t, ok)
+	assert.Equal(t, "0.7.0", caps.(*GNMICapabilities).Version)
+}
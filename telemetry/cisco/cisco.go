@@ -7,6 +7,7 @@ import (
 	"github.com/yahoo/panoptes-stream/telemetry"
 	"github.com/yahoo/panoptes-stream/telemetry/cisco/gnmi"
 	"github.com/yahoo/panoptes-stream/telemetry/cisco/mdt"
+	"github.com/yahoo/panoptes-stream/telemetry/dialout"
 )
 
 // Register Cisco telemetries
@@ -14,3 +15,8 @@ func Register(telemetryRegistrar *telemetry.Registrar) {
 	telemetryRegistrar.Register("cisco.gnmi", gnmi.Version(), gnmi.New)
 	telemetryRegistrar.Register("cisco.mdt", mdt.Version(), mdt.New)
 }
+
+// RegisterDialout Cisco dial-out telemetries
+func RegisterDialout(dialoutRegistrar *dialout.Registrar) {
+	dialoutRegistrar.Register("cisco.mdt", mdt.Version(), mdt.NewDialout)
+}
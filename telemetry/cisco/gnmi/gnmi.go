@@ -24,9 +24,9 @@ var gnmiVersion = "0.0.1"
 // GNMI represents a GNMI.
 type GNMI struct {
 	conn          *grpc.ClientConn
-	subscriptions []*gpb.Subscription
+	subscriptions []*telemetry.GNMISubscriptionGroup
 
-	dataChan chan *gpb.SubscribeResponse
+	dataChan chan telemetry.GNMIResponse
 	outChan  telemetry.ExtDSChan
 	logger   *zap.Logger
 
@@ -50,8 +50,8 @@ func New(logger *zap.Logger, conn *grpc.ClientConn, sensors []*config.Sensor, ou
 	return &GNMI{
 		logger:        logger,
 		conn:          conn,
-		subscriptions: telemetry.GetGNMISubscriptions(sensors),
-		dataChan:      make(chan *gpb.SubscribeResponse, 100),
+		subscriptions: telemetry.GetGNMISubscriptionGroups(sensors),
+		dataChan:      make(chan telemetry.GNMIResponse, 100),
 		outChan:       outChan,
 		pathOutput:    telemetry.GetPathOutput(sensors),
 		defaultOutput: telemetry.GetDefaultOutput(sensors),
@@ -63,47 +63,19 @@ func New(logger *zap.Logger, conn *grpc.ClientConn, sensors []*config.Sensor, ou
 func (g *GNMI) Start(ctx context.Context) error {
 	defer status.Unregister(status.Labels{"host": g.conn.Target()}, g.metrics)
 
-	client := gpb.NewGNMIClient(g.conn)
-	subReq := &gpb.SubscribeRequest{
-		Request: &gpb.SubscribeRequest_Subscribe{
-			Subscribe: &gpb.SubscriptionList{
-				Mode:         gpb.SubscriptionList_STREAM,
-				Encoding:     gpb.Encoding(gpb.Encoding_value["PROTO"]),
-				Subscription: g.subscriptions,
-				UpdatesOnly:  false,
-			},
-		},
-	}
-
-	subClient, err := client.Subscribe(ctx)
-	if err != nil {
-		return err
-	}
-
-	err = subClient.Send(subReq)
-	if err != nil {
-		return err
-	}
-
 	workers := config.GetEnvInt("CISCO_GNMI_WORKERS", 1)
 	for i := 0; i < workers; i++ {
 		go g.worker(ctx)
 	}
 
-	for {
-		resp, err := subClient.Recv()
-		if err != nil && ctx.Err() == nil {
-			return err
-		}
-
-		if ctx.Err() != nil {
-			return nil
-		}
+	client := gpb.NewGNMIClient(g.conn)
 
-		g.dataChan <- resp
-		g.metrics["gRPCDataTotal"].Inc()
+	encoding, subscriptions, err := telemetry.GNMICapabilitiesHandshake(ctx, client, g.conn.Target(), "cisco.gnmi", gpb.Encoding(gpb.Encoding_value["PROTO"]), g.subscriptions, g.logger)
+	if err != nil {
+		return err
 	}
 
+	return telemetry.GNMISubscribe(ctx, client, encoding, subscriptions, g.dataChan, g.metrics["gRPCDataTotal"])
 }
 
 func (g *GNMI) worker(ctx context.Context) {
@@ -122,6 +94,14 @@ func (g *GNMI) worker(ctx context.Context) {
 
 			start = time.Now()
 
+			if d.GetSyncResponse() {
+				if err := telemetry.SyncResponse(g.outChan, g.pathOutput, d.Group.Paths, systemID); err != nil {
+					g.metrics["dropsTotal"].Inc()
+					g.logger.Error("cisco.gnmi", zap.Error(err))
+				}
+				continue
+			}
+
 			resp, ok := d.Response.(*gpb.SubscribeResponse_Update)
 			if !ok {
 				continue
@@ -188,6 +168,33 @@ func (g *GNMI) datastore(buf *bytes.Buffer, n *gpb.Notification, systemID string
 		}
 	}
 
+	for _, path := range n.Delete {
+		buf.Reset()
+
+		key, keyLabels := telemetry.GetKey(buf, path.Elem)
+		labels = telemetry.MergeLabels(keyLabels, prefixLabels, prefix)
+
+		dataStore := telemetry.DataStore{
+			"prefix":    prefix,
+			"labels":    labels,
+			"timestamp": n.Timestamp,
+			"system_id": systemID,
+			"key":       key,
+			"value":     nil,
+			"operation": telemetry.OperationDelete,
+		}
+
+		select {
+		case g.outChan <- telemetry.ExtDataStore{
+			DS:     dataStore,
+			Output: output,
+		}:
+		default:
+			g.metrics["dropsTotal"].Inc()
+			g.logger.Warn("cisco.gnmi", zap.String("error", "dataset drop"))
+		}
+	}
+
 	return nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
 
@@ -77,6 +78,30 @@ func TestDatastore(t *testing.T) {
 	}
 }
 
+func TestDatastoreDelete(t *testing.T) {
+	cfg := config.NewMockConfig()
+	ch := make(telemetry.ExtDSChan, 20)
+	g := GNMI{
+		logger:     cfg.Logger(),
+		pathOutput: map[string]string{"/interfaces/interface/state/counters/": "out::out"},
+		outChan:    ch,
+	}
+
+	md := mock.CiscoXRInterface()
+	md.Delete = []*gpb.Path{{Elem: []*gpb.PathElem{{Name: "in-octets"}}}}
+	md.Update = nil
+
+	err := g.datastore(new(bytes.Buffer), md, "127.0.0.1")
+	assert.NoError(t, err)
+	assert.Len(t, ch, 1)
+
+	m := <-ch
+	assert.Equal(t, telemetry.OperationDelete, m.DS["operation"])
+	assert.Equal(t, "in-octets", m.DS["key"])
+	assert.Nil(t, m.DS["value"])
+	assert.Equal(t, "out::out", m.Output)
+}
+
 func TestWithMockServer(t *testing.T) {
 	var (
 		addr    = "127.0.0.1:50555"
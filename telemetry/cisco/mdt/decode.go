@@ -0,0 +1,394 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package mdt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	mdt "github.com/cisco-ie/nx-telemetry-proto/telemetry_bis"
+)
+
+// mdtData represents the decoded compact GPB or JSON telemetry
+type mdtData struct {
+	nodeID       string
+	subscription string
+	path         string
+	rows         []row
+}
+
+type row struct {
+	timestamp uint64
+	keys      map[string]string
+	kv        map[string]interface{}
+}
+
+type jsonTelemetry struct {
+	NodeID       string        `json:"node_id_str"`
+	Subscription string        `json:"subscription_id_str"`
+	EncodingPath string        `json:"encoding_path"`
+	MsgTimestamp uint64        `json:"msg_timestamp"`
+	DataJSON     []jsonDataRow `json:"data_json"`
+}
+
+type jsonDataRow struct {
+	Timestamp uint64      `json:"timestamp"`
+	Keys      interface{} `json:"keys"`
+	Content   interface{} `json:"content"`
+}
+
+func isJSON(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+// decodeJSON decodes the JSON encoded telemetry.
+func decodeJSON(data []byte) (*mdtData, error) {
+	var tm jsonTelemetry
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&tm); err != nil {
+		return nil, err
+	}
+
+	d := &mdtData{
+		nodeID:       tm.NodeID,
+		subscription: tm.Subscription,
+		path:         tm.EncodingPath,
+	}
+
+	for _, r := range tm.DataJSON {
+		rw := row{
+			timestamp: getTimestamp(r.Timestamp, tm.MsgTimestamp),
+			keys:      make(map[string]string),
+			kv:        make(map[string]interface{}),
+		}
+
+		// keys is an object or a list of objects
+		switch keys := r.Keys.(type) {
+		case map[string]interface{}:
+			for k, v := range keys {
+				rw.keys[k] = fmt.Sprint(v)
+			}
+		case []interface{}:
+			for _, key := range keys {
+				if m, ok := key.(map[string]interface{}); ok {
+					for k, v := range m {
+						rw.keys[k] = fmt.Sprint(v)
+					}
+				}
+			}
+		}
+
+		d.rows = append(d.rows, flatten(rw, r.Content)...)
+	}
+
+	return d, nil
+}
+
+// decodeCompact decodes the compact GPB rows based on the
+// encoding path's schema which loaded from the proto files.
+func decodeCompact(tm *mdt.Telemetry) (*mdtData, error) {
+	s, ok := getSchema(tm.GetEncodingPath())
+	if !ok || s.content == nil {
+		return nil, fmt.Errorf("schema not found - %s", tm.GetEncodingPath())
+	}
+
+	d := &mdtData{
+		nodeID:       tm.GetNodeIdStr(),
+		subscription: tm.GetSubscriptionIdStr(),
+		path:         tm.GetEncodingPath(),
+	}
+
+	for _, r := range tm.DataGpb.GetRow() {
+		rw := row{
+			timestamp: getTimestamp(r.Timestamp, tm.MsgTimestamp),
+			keys:      make(map[string]string),
+			kv:        make(map[string]interface{}),
+		}
+
+		if s.keys != nil {
+			keys, err := decodeMessage(s.keys, r.Keys)
+			if err != nil {
+				return nil, err
+			}
+
+			for k, v := range keys {
+				rw.keys[k] = fmt.Sprint(v)
+			}
+		}
+
+		content, err := decodeMessage(s.content, r.Content)
+		if err != nil {
+			return nil, err
+		}
+
+		d.rows = append(d.rows, flatten(rw, content)...)
+	}
+
+	return d, nil
+}
+
+// flatten converts the nested content to the row's key values e.g. state/counters/in-octets
+// the list entries (objects) are flattened to their own rows which labeled by the list key
+// and the entry index e.g. af-data=0 and the rows without key value are removed.
+func flatten(rw row, content interface{}) []row {
+	var (
+		rows   = []row{rw}
+		result []row
+	)
+
+	flattenValue(&rows, rw, "", content)
+
+	for _, r := range rows {
+		if len(r.kv) > 0 {
+			result = append(result, r)
+		}
+	}
+
+	return result
+}
+
+func flattenValue(rows *[]row, rw row, key string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			if key != "" {
+				flattenValue(rows, rw, key+"/"+k, val)
+			} else {
+				flattenValue(rows, rw, k, val)
+			}
+		}
+	case []interface{}:
+		var values []interface{}
+		for i, val := range v {
+			if _, ok := val.(map[string]interface{}); ok {
+				entry := row{
+					timestamp: rw.timestamp,
+					keys:      make(map[string]string),
+					kv:        make(map[string]interface{}),
+				}
+
+				for k, v := range rw.keys {
+					entry.keys[k] = v
+				}
+				entry.keys[key] = strconv.Itoa(i)
+
+				*rows = append(*rows, entry)
+				flattenValue(rows, entry, key, val)
+			} else if val = scalarValue(val); val != nil {
+				values = append(values, val)
+			}
+		}
+
+		if len(values) > 0 {
+			rw.kv[key] = values
+		}
+	default:
+		if val := scalarValue(v); val != nil {
+			rw.kv[key] = val
+		}
+	}
+}
+
+// scalarValue converts the JSON numbers to uint64, int64 or float64 respectively.
+func scalarValue(value interface{}) interface{} {
+	v, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+
+	if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+		return u
+	} else if i, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+		return i
+	} else if f, err := v.Float64(); err == nil {
+		return f
+	}
+
+	return nil
+}
+
+// decodeMessage decodes the protobuf wire format based on the message
+// descriptor, the unknown fields are skipped and the groups are not supported.
+func decodeMessage(m *message, b []byte) (map[string]interface{}, error) {
+	var result = make(map[string]interface{})
+
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errors.New("invalid tag")
+		}
+		b = b[n:]
+
+		number, wireType := int(tag>>3), int(tag&7)
+		f := m.fields[number]
+
+		var (
+			value interface{}
+			err   error
+		)
+
+		switch wireType {
+		case 0:
+			var v uint64
+			v, n = binary.Uvarint(b)
+			if n <= 0 {
+				return nil, errors.New("invalid varint")
+			}
+			b = b[n:]
+
+			if f != nil {
+				value = varintValue(f.kind, v)
+			}
+		case 1:
+			if len(b) < 8 {
+				return nil, errors.New("invalid fixed64")
+			}
+			v := binary.LittleEndian.Uint64(b)
+			b = b[8:]
+
+			if f != nil {
+				value = fixed64Value(f.kind, v)
+			}
+		case 5:
+			if len(b) < 4 {
+				return nil, errors.New("invalid fixed32")
+			}
+			v := binary.LittleEndian.Uint32(b)
+			b = b[4:]
+
+			if f != nil {
+				value = fixed32Value(f.kind, v)
+			}
+		case 2:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return nil, errors.New("invalid length")
+			}
+			data := b[n : n+int(l)]
+			b = b[n+int(l):]
+
+			if f != nil {
+				value, err = bytesValue(f, data)
+				if err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, fmt.Errorf("wire type %d not supported", wireType)
+		}
+
+		if f == nil || value == nil {
+			continue
+		}
+
+		if !f.repeated {
+			result[f.name] = value
+			continue
+		}
+
+		list, _ := result[f.name].([]interface{})
+		if packed, ok := value.([]interface{}); ok {
+			list = append(list, packed...)
+		} else {
+			list = append(list, value)
+		}
+		result[f.name] = list
+	}
+
+	return result, nil
+}
+
+func bytesValue(f *field, data []byte) (interface{}, error) {
+	switch {
+	case f.message != nil:
+		return decodeMessage(f.message, data)
+	case f.kind == "string":
+		return string(data), nil
+	case f.kind == "bytes":
+		return data, nil
+	case f.repeated:
+		return packedValues(f.kind, data)
+	}
+
+	return nil, nil
+}
+
+func packedValues(kind string, b []byte) ([]interface{}, error) {
+	var values []interface{}
+
+	for len(b) > 0 {
+		switch kind {
+		case "fixed64", "sfixed64", "double":
+			if len(b) < 8 {
+				return nil, errors.New("invalid packed fixed64")
+			}
+			values = append(values, fixed64Value(kind, binary.LittleEndian.Uint64(b)))
+			b = b[8:]
+		case "fixed32", "sfixed32", "float":
+			if len(b) < 4 {
+				return nil, errors.New("invalid packed fixed32")
+			}
+			values = append(values, fixed32Value(kind, binary.LittleEndian.Uint32(b)))
+			b = b[4:]
+		default:
+			v, n := binary.Uvarint(b)
+			if n <= 0 {
+				return nil, errors.New("invalid packed varint")
+			}
+			values = append(values, varintValue(kind, v))
+			b = b[n:]
+		}
+	}
+
+	return values, nil
+}
+
+func varintValue(kind string, v uint64) interface{} {
+	switch kind {
+	case "int32", "enum":
+		return int32(v)
+	case "int64":
+		return int64(v)
+	case "uint32":
+		return uint32(v)
+	case "sint32":
+		return int32(uint32(v)>>1) ^ -int32(v&1)
+	case "sint64":
+		return int64(v>>1) ^ -int64(v&1)
+	case "bool":
+		return v != 0
+	}
+
+	return v
+}
+
+func fixed64Value(kind string, v uint64) interface{} {
+	switch kind {
+	case "sfixed64":
+		return int64(v)
+	case "double":
+		return math.Float64frombits(v)
+	}
+
+	return v
+}
+
+func fixed32Value(kind string, v uint32) interface{} {
+	switch kind {
+	case "sfixed32":
+		return int32(v)
+	case "float":
+		return math.Float32frombits(v)
+	}
+
+	return v
+}
@@ -0,0 +1,174 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package mdt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strconv"
+	"testing"
+
+	mdtTelemetry "github.com/cisco-ie/nx-telemetry-proto/telemetry_bis"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+var jsonTelemetryData = []byte(`{
+	"node_id_str": "ios",
+	"subscription_id_str": "Sub1",
+	"encoding_path": "Cisco-IOS-XR-infra-statsd-oper:infra-statistics/interfaces/interface/latest/generic-counters",
+	"msg_timestamp": 1597098790358,
+	"data_json": [
+		{
+			"timestamp": 1597098791076,
+			"keys": [{"interface-name": "GigabitEthernet0/0/0/0"}],
+			"content": {
+				"packets-received": 1023,
+				"last-data-time": -1,
+				"rate": {"load": 0.5},
+				"queues": [1, 2]
+			}
+		}
+	]
+}`)
+
+func tag(number, wireType int) []byte {
+	return uvarint(uint64(number<<3 | wireType))
+}
+
+func uvarint(v uint64) []byte {
+	b := make([]byte, binary.MaxVarintLen64)
+	return b[:binary.PutUvarint(b, v)]
+}
+
+func lengthDelimited(number int, data []byte) []byte {
+	b := append(tag(number, 2), uvarint(uint64(len(data)))...)
+	return append(b, data...)
+}
+
+func TestDecodeJSON(t *testing.T) {
+	d, err := decodeJSON(jsonTelemetryData)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "ios", d.nodeID)
+	assert.Equal(t, "Sub1", d.subscription)
+	assert.Len(t, d.rows, 1)
+
+	r := d.rows[0]
+	assert.Equal(t, uint64(1597098791076), r.timestamp)
+	assert.Equal(t, map[string]string{"interface-name": "GigabitEthernet0/0/0/0"}, r.keys)
+	assert.Equal(t, uint64(1023), r.kv["packets-received"])
+	assert.Equal(t, int64(-1), r.kv["last-data-time"])
+	assert.Equal(t, 0.5, r.kv["rate/load"])
+	assert.Equal(t, []interface{}{uint64(1), uint64(2)}, r.kv["queues"])
+
+	_, err = decodeJSON([]byte("{invalid"))
+	assert.Error(t, err)
+}
+
+func TestDecodeJSONList(t *testing.T) {
+	d, err := decodeJSON([]byte(`{
+		"node_id_str": "ios",
+		"data_json": [
+			{
+				"timestamp": 1597098791076,
+				"keys": {"neighbor-address": "10.0.0.1"},
+				"content": {
+					"messages-received": 10,
+					"af-data": [
+						{"af-name": "ipv4", "prefixes-accepted": 100},
+						{"af-name": "ipv6", "prefixes-accepted": 200}
+					]
+				}
+			}
+		]
+	}`))
+	assert.NoError(t, err)
+	assert.Len(t, d.rows, 3)
+
+	assert.Equal(t, map[string]string{"neighbor-address": "10.0.0.1"}, d.rows[0].keys)
+	assert.Equal(t, map[string]interface{}{"messages-received": uint64(10)}, d.rows[0].kv)
+
+	for i, afName := range []string{"ipv4", "ipv6"} {
+		r := d.rows[i+1]
+		assert.Equal(t, uint64(1597098791076), r.timestamp)
+		assert.Equal(t, map[string]string{"neighbor-address": "10.0.0.1", "af-data": strconv.Itoa(i)}, r.keys)
+		assert.Equal(t, afName, r.kv["af-data/af-name"])
+		assert.Equal(t, uint64(100*(i+1)), r.kv["af-data/prefixes-accepted"])
+	}
+}
+
+func TestDecodeCompact(t *testing.T) {
+	loadTestProtos(t)
+
+	keys := lengthDelimited(1, []byte("GigabitEthernet0/0/0/0"))
+
+	var content []byte
+	content = append(content, tag(1, 0)...)
+	content = append(content, uvarint(1023)...)
+	content = append(content, tag(3, 0)...)
+	content = append(content, uvarint(3)...)
+	rate := tag(1, 1)
+	rate = append(rate, make([]byte, 8)...)
+	binary.LittleEndian.PutUint64(rate[1:], math.Float64bits(1.5))
+	content = append(content, lengthDelimited(4, rate)...)
+	content = append(content, lengthDelimited(5, []byte{1, 2})...)
+	content = append(content, tag(6, 0)...)
+	content = append(content, uvarint(1)...)
+	// unknown field
+	content = append(content, tag(99, 0)...)
+	content = append(content, uvarint(5)...)
+
+	tm := &mdtTelemetry.Telemetry{
+		NodeId:       &mdtTelemetry.Telemetry_NodeIdStr{NodeIdStr: "ios"},
+		Subscription: &mdtTelemetry.Telemetry_SubscriptionIdStr{SubscriptionIdStr: "Sub1"},
+		EncodingPath: "Cisco-IOS-XR-infra-statsd-oper:infra-statistics/interfaces/interface/latest/generic-counters",
+		MsgTimestamp: 1597098790358,
+		DataGpb: &mdtTelemetry.TelemetryGPBTable{
+			Row: []*mdtTelemetry.TelemetryRowGPB{
+				{Timestamp: 1597098791076, Keys: keys, Content: content},
+			},
+		},
+	}
+
+	d, err := decodeCompact(tm)
+	assert.NoError(t, err)
+	assert.Len(t, d.rows, 1)
+
+	r := d.rows[0]
+	assert.Equal(t, map[string]string{"interface_name": "GigabitEthernet0/0/0/0"}, r.keys)
+	assert.Equal(t, uint64(1023), r.kv["packets_received"])
+	assert.Equal(t, int32(-2), r.kv["delta"])
+	assert.Equal(t, 1.5, r.kv["rate/input"])
+	assert.Equal(t, []interface{}{uint32(1), uint32(2)}, r.kv["queues"])
+	assert.Equal(t, int32(1), r.kv["state"])
+	assert.Len(t, r.kv, 5)
+
+	// schema not found
+	tm.EncodingPath = "openconfig-interfaces:interfaces/interface"
+	_, err = decodeCompact(tm)
+	assert.Error(t, err)
+}
+
+func TestDatastoreJSON(t *testing.T) {
+	ch := make(telemetry.ExtDSChan, 10)
+
+	m := &MDT{
+		pathOutput: map[string]string{"Sub1": "console::stdout"},
+		outChan:    ch,
+		systemID:   "127.0.0.1",
+	}
+
+	err := m.datastore(new(bytes.Buffer), jsonTelemetryData)
+	assert.NoError(t, err)
+	assert.Len(t, ch, 4)
+
+	resp := <-ch
+	assert.Equal(t, "console::stdout", resp.Output)
+	assert.Equal(t, "127.0.0.1", resp.DS["system_id"])
+	assert.Equal(t, "GigabitEthernet0/0/0/0", resp.DS["labels"].(map[string]string)["interface-name"])
+	assert.Equal(t, "Sub1", resp.DS["labels"].(map[string]string)["subscriptionId"])
+}
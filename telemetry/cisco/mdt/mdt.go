@@ -6,6 +6,7 @@ package mdt
 import (
 	"bytes"
 	"context"
+	"errors"
 	"net"
 	"os"
 	"strconv"
@@ -22,17 +23,24 @@ import (
 )
 
 const (
-	gpb = iota + 2
-	gpbkv
-	json
+	encodingGPB = iota + 2
+	encodingGPBKV
+	encodingJSON
 )
 
+var encodings = map[string]int64{
+	"":      encodingGPBKV,
+	"gpb":   encodingGPB,
+	"gpbkv": encodingGPBKV,
+	"json":  encodingJSON,
+}
+
 var mdtVersion = "0.0.1"
 
 // MDT represents Model-Driven Telemetry.
 type MDT struct {
 	conn          *grpc.ClientConn
-	subscriptions []string
+	subscriptions map[int64][]string
 
 	dataChan chan []byte
 	outChan  telemetry.ExtDSChan
@@ -55,16 +63,23 @@ func New(logger *zap.Logger, conn *grpc.ClientConn, sensors []*config.Sensor, ou
 	status.Register(status.Labels{"host": conn.Target()}, metrics)
 
 	m := &MDT{
-		conn:       conn,
-		outChan:    outChan,
-		logger:     logger,
-		dataChan:   make(chan []byte, 1000),
-		pathOutput: make(map[string]string),
-		metrics:    metrics,
+		conn:          conn,
+		outChan:       outChan,
+		logger:        logger,
+		dataChan:      make(chan []byte, 1000),
+		subscriptions: make(map[int64][]string),
+		pathOutput:    make(map[string]string),
+		metrics:       metrics,
 	}
 
 	for _, sensor := range sensors {
-		m.subscriptions = append(m.subscriptions, sensor.Subscription)
+		encoding, ok := encodings[sensor.Encoding]
+		if !ok {
+			logger.Error("cisco.mdt", zap.String("msg", "invalid encoding"), zap.String("encoding", sensor.Encoding))
+			continue
+		}
+
+		m.subscriptions[encoding] = append(m.subscriptions[encoding], sensor.Subscription)
 		m.pathOutput[sensor.Subscription] = sensor.Output
 	}
 
@@ -73,27 +88,49 @@ func New(logger *zap.Logger, conn *grpc.ClientConn, sensors []*config.Sensor, ou
 	return m
 }
 
-// Start gets stream metrics and fan-out to workers.
+// Start gets stream metrics and fan-out to workers, the subscriptions
+// with the same encoding are requested together.
 func (m *MDT) Start(ctx context.Context) error {
+	var errChan = make(chan error, len(m.subscriptions))
 
-	subsArgs := &mdtGRPC.SubscribeRequest{
-		RequestId:     int64(os.Getpid()),
-		Encode:        gpbkv,
-		Subscriptions: m.subscriptions,
-		Qos:           &mdtGRPC.QOSMarking{Marking: 10},
+	if len(m.subscriptions) < 1 {
+		return errors.New("subscriptions not available")
 	}
 
+	// terminates the rest of subscriptions once a subscription failed
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	client := mdtGRPC.NewGRPCConfigOperClient(m.conn)
-	stream, err := client.CreateSubs(ctx, subsArgs)
-	if err != nil {
-		return err
-	}
 
 	workers := config.GetEnvInt("CISCO_MDT_WORKERS", 1)
 	for i := 0; i < workers; i++ {
 		go m.worker(ctx)
 	}
 
+	for encoding, subscriptions := range m.subscriptions {
+		subsArgs := &mdtGRPC.SubscribeRequest{
+			RequestId:     int64(os.Getpid()) + encoding,
+			Encode:        encoding,
+			Subscriptions: subscriptions,
+			Qos:           &mdtGRPC.QOSMarking{Marking: 10},
+		}
+
+		go func() {
+			errChan <- m.subscribe(subCtx, client, subsArgs)
+		}()
+	}
+
+	// returns once a subscription terminated
+	return <-errChan
+}
+
+func (m *MDT) subscribe(ctx context.Context, client mdtGRPC.GRPCConfigOperClient, subsArgs *mdtGRPC.SubscribeRequest) error {
+	stream, err := client.CreateSubs(ctx, subsArgs)
+	if err != nil {
+		return err
+	}
+
 	for {
 		reply, err := stream.Recv()
 		// TODO handle error io.EOF
@@ -106,9 +143,20 @@ func (m *MDT) Start(ctx context.Context) error {
 			continue
 		}
 
-		m.dataChan <- reply.Data
-	}
+		if len(reply.Errors) > 0 {
+			m.metrics["errorsTotal"].Inc()
+			m.logger.Error("cisco.mdt", zap.String("error", reply.Errors))
+			continue
+		}
+
+		m.metrics["gRPCDataTotal"].Inc()
 
+		select {
+		case m.dataChan <- reply.Data:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
 }
 
 func (m *MDT) worker(ctx context.Context) {
@@ -131,17 +179,81 @@ func (m *MDT) worker(ctx context.Context) {
 }
 
 func (m *MDT) datastore(buf *bytes.Buffer, data []byte) error {
+	if isJSON(data) {
+		d, err := decodeJSON(data)
+		if err != nil {
+			return err
+		}
+
+		m.rowsHandler(d)
+
+		return nil
+	}
+
 	tm := &mdt.Telemetry{}
 	err := proto.Unmarshal(data, tm)
 	if err != nil {
 		return err
 	}
 
+	if tm.DataGpb != nil {
+		d, err := decodeCompact(tm)
+		if err != nil {
+			return err
+		}
+
+		m.rowsHandler(d)
+
+		return nil
+	}
+
 	m.handler(buf, tm)
 
 	return nil
 }
 
+// rowsHandler handles the compact GPB and JSON rows.
+func (m *MDT) rowsHandler(d *mdtData) {
+	output, ok := m.pathOutput[d.subscription]
+	if !ok {
+		m.logger.Error("cisco.mdt", zap.String("msg", "output not found"))
+		return
+	}
+
+	for _, r := range d.rows {
+		labels := map[string]string{
+			"subscriptionId": d.subscription,
+			"nodeId":         d.nodeID,
+			"path":           d.path,
+		}
+
+		for k, v := range r.keys {
+			labels[k] = v
+		}
+
+		for key, value := range r.kv {
+			dataStore := telemetry.DataStore{
+				"prefix":    d.path,
+				"labels":    labels,
+				"timestamp": r.timestamp,
+				"system_id": m.systemID,
+				"key":       key,
+				"value":     value,
+			}
+
+			select {
+			case m.outChan <- telemetry.ExtDataStore{
+				DS:     dataStore,
+				Output: output,
+			}:
+			default:
+				m.metrics["dropsTotal"].Inc()
+				m.logger.Warn("cisco.mdt", zap.String("error", "dataset drop"))
+			}
+		}
+	}
+}
+
 func (m *MDT) handler(buf *bytes.Buffer, tm *mdt.Telemetry) {
 	var (
 		prefix, output string
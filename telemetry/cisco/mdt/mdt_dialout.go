@@ -6,7 +6,9 @@ package mdt
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"errors"
+	"fmt"
 	"net"
 	"sync"
 
@@ -14,7 +16,7 @@ import (
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/peer"
 
-	dialout "github.com/cisco-ie/nx-telemetry-proto/mdt_dialout"
+	mdtDialout "github.com/cisco-ie/nx-telemetry-proto/mdt_dialout"
 	mdt "github.com/cisco-ie/nx-telemetry-proto/telemetry_bis"
 	"github.com/golang/protobuf/proto"
 	"go.uber.org/zap"
@@ -23,57 +25,108 @@ import (
 	"github.com/yahoo/panoptes-stream/secret"
 	"github.com/yahoo/panoptes-stream/status"
 	"github.com/yahoo/panoptes-stream/telemetry"
+	"github.com/yahoo/panoptes-stream/telemetry/dialout"
 )
 
+// dialoutService is the sensor service name which binds
+// a device's subscriptions to the outputs.
+const dialoutService = "cisco.mdt.dialout"
+
+// defaultMaxMsgSize is the maximum reassembled message size in bytes.
+const defaultMaxMsgSize = 32 << 20
+
+// maxPendingReqs is the maximum incomplete chunked messages per stream.
+const maxPendingReqs = 16
+
 // Dialout represents MDT dial-out.
 type Dialout struct {
 	ctx        context.Context
+	cancel     context.CancelFunc
 	cfg        config.Config
-	dataChan   chan []byte
+	conf       config.DialoutService
+	srv        *grpc.Server
+	dataChan   chan dialoutData
 	outChan    telemetry.ExtDSChan
 	logger     *zap.Logger
 	metrics    map[string]status.Metrics
 	pathOutput map[string]string
+	peerOutput map[string]map[string]string
+	hosts      map[string]string
+	devices    map[string]config.Device
+	identities map[string]string
+	peers      map[string]*dialoutPeer
+	closeTLS   func()
 
 	sync.RWMutex
 }
 
+// dialoutPeer represents a connected device with its metrics
+// which they're shared between the device's streams.
+type dialoutPeer struct {
+	host    string
+	refs    int
+	labels  map[string]string
+	metrics map[string]status.Metrics
+}
+
+type dialoutData struct {
+	peer *dialoutPeer
+	data []byte
+}
+
+// chunks reassembles the chunked messages of a stream per request id,
+// the oldest incomplete messages are evicted once the stream exceeds the
+// maximum incomplete messages or the maximum buffered size.
+type chunks struct {
+	bufs    map[int64]*bytes.Buffer
+	reqs    []int64
+	size    int
+	maxSize int
+}
+
 // NewDialout returns a new instance of MDT dial-out.
-func NewDialout(ctx context.Context, cfg config.Config, outChan telemetry.ExtDSChan) *Dialout {
+func NewDialout(ctx context.Context, cfg config.Config, conf config.DialoutService, outChan telemetry.ExtDSChan) dialout.Service {
 	var metrics = make(map[string]status.Metrics)
 
-	metrics["dropsTotal"] = status.NewCounter("cisco_mdt_drops_total", "")
+	metrics["sessionsCurrent"] = status.NewGauge("cisco_mdt_dialout_sessions", "")
+	metrics["unknownPeersTotal"] = status.NewCounter("cisco_mdt_dialout_unknown_peers_total", "")
+	metrics["unauthorizedPeersTotal"] = status.NewCounter("cisco_mdt_dialout_unauthorized_peers_total", "")
 
-	status.Register(status.Labels{}, metrics)
+	status.Register(status.Labels{"service": conf.Name}, metrics)
 
 	m := &Dialout{
-		ctx:        ctx,
-		cfg:        cfg,
-		outChan:    outChan,
-		logger:     cfg.Logger(),
-		dataChan:   make(chan []byte, 1000),
-		pathOutput: make(map[string]string),
-		metrics:    metrics,
+		cfg:      cfg,
+		conf:     conf,
+		outChan:  outChan,
+		logger:   cfg.Logger(),
+		dataChan: make(chan dialoutData, 1000),
+		metrics:  metrics,
+		peers:    make(map[string]*dialoutPeer),
 	}
 
-	for _, sensor := range cfg.Sensors() {
-		m.pathOutput[sensor.Subscription] = sensor.Output
-	}
+	m.pathOutput, m.peerOutput, m.hosts, m.devices = getOutputs(cfg)
+	m.identities = dialout.GetIdentities(m.devices)
+	m.ctx, m.cancel = context.WithCancel(ctx)
 
 	return m
 }
 
-// Start creates workers and starts gRPC server.
+// Start creates workers and starts the server based on the transport.
 func (m *Dialout) Start() error {
-	var grpcSrvOpts []grpc.ServerOption
-
-	tlsConf := m.cfg.Global().Dialout.TLSConfig
-	conf := m.cfg.Global().Dialout.Services["cisco.mdt"]
+	var conf = m.conf
 
 	if conf.Addr == "" {
 		return errors.New("address is empty")
 	}
 
+	if _, err := dialout.GetTLSClientAuth(conf.ClientAuth, conf.TLSConfig); err != nil {
+		return err
+	}
+
+	if conf.ClientAuth != dialout.ClientAuthNone && conf.Transport == "udp" {
+		return errors.New("client auth requires TLS")
+	}
+
 	if conf.Workers < 1 {
 		conf.Workers = 2
 	}
@@ -82,14 +135,41 @@ func (m *Dialout) Start() error {
 		go m.worker()
 	}
 
-	ln, err := net.Listen("tcp", conf.Addr)
+	var err error
+
+	switch conf.Transport {
+	case "", "grpc":
+		err = m.startGRPC()
+	case "tcp":
+		err = m.startTCP()
+	case "udp":
+		err = m.startUDP()
+	default:
+		err = fmt.Errorf("transport %s not supported", conf.Transport)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	m.logger.Info("cisco.mdt.dialout", zap.String("address", conf.Addr), zap.String("transport", conf.Transport),
+		zap.Bool("tls", conf.TLSConfig.Enabled))
+
+	return nil
+}
+
+func (m *Dialout) startGRPC() error {
+	var grpcSrvOpts []grpc.ServerOption
+
+	ln, err := net.Listen("tcp", m.conf.Addr)
 	if err != nil {
 		return err
 	}
 
-	if tlsConf.Enabled {
-		tlsConfig, err := secret.GetTLSServerConfig(&tlsConf)
+	if m.conf.TLSConfig.Enabled {
+		tlsConfig, err := m.getTLSConfig()
 		if err != nil {
+			ln.Close()
 			return err
 		}
 
@@ -97,57 +177,163 @@ func (m *Dialout) Start() error {
 		grpcSrvOpts = append(grpcSrvOpts, creds)
 	}
 
-	srv := grpc.NewServer(grpcSrvOpts...)
-	dialout.RegisterGRPCMdtDialoutServer(srv, m)
-	go srv.Serve(ln)
+	grpcSrvOpts = append(grpcSrvOpts, telemetry.GetTransportServerOpts(m.cfg.Global().DeviceOptions)...)
 
-	m.logger.Info("cisco.mdt.dialout", zap.String("address", conf.Addr), zap.Bool("tls", m.cfg.Global().Dialout.TLSConfig.Enabled))
+	m.srv = grpc.NewServer(grpcSrvOpts...)
+	mdtDialout.RegisterGRPCMdtDialoutServer(m.srv, m)
+	go m.srv.Serve(ln)
 
 	return nil
 }
 
-// Update updates path to output once the configuration changed.
+// Stop stops the server and workers.
+func (m *Dialout) Stop() {
+	if m.srv != nil {
+		m.srv.Stop()
+	}
+
+	m.cancel()
+
+	if m.closeTLS != nil {
+		m.closeTLS()
+	}
+
+	status.Unregister(status.Labels{"service": m.conf.Name}, m.metrics)
+}
+
+// Update updates path and peers to output once the configuration changed.
 func (m *Dialout) Update() {
 	m.Lock()
 	defer m.Unlock()
 
-	m.pathOutput = make(map[string]string)
-	for _, sensor := range m.cfg.Sensors() {
-		m.pathOutput[sensor.Subscription] = sensor.Output
-	}
+	m.pathOutput, m.peerOutput, m.hosts, m.devices = getOutputs(m.cfg)
+	m.identities = dialout.GetIdentities(m.devices)
 }
 
-// MdtDialout gets stream metrics and fan-out to workers.
-func (m *Dialout) MdtDialout(stream dialout.GRPCMdtDialout_MdtDialoutServer) error {
-	var buf *bytes.Buffer
+// MdtDialout gets stream metrics, reassembles the chunked
+// messages per request id and fan-out to workers.
+func (m *Dialout) MdtDialout(stream mdtDialout.GRPCMdtDialout_MdtDialoutServer) error {
+	var (
+		addr   = "unknown"
+		state  *tls.ConnectionState
+		chunks = newChunks(m.getMaxMsgSize())
+	)
 
 	p, ok := peer.FromContext(stream.Context())
-
 	if !ok {
 		m.logger.Warn("cisco.mdt.dialout", zap.String("event", "connect"), zap.String("host", "peer address is unavailable"))
 	} else {
-		m.logger.Info("cisco.mtd.dialout", zap.String("event", "connect"), zap.String("peer", p.Addr.String()))
+		addr = p.Addr.String()
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			state = &info.State
+		}
+		m.logger.Info("cisco.mdt.dialout", zap.String("event", "connect"), zap.String("peer", addr))
+	}
+
+	dp, err := m.addPeer(addr, state)
+	if err != nil {
+		return err
 	}
+	defer m.removePeer(dp)
 
 	for {
 		dialoutArgs, err := stream.Recv()
 		if err != nil {
+			m.logger.Info("cisco.mdt.dialout", zap.String("event", "disconnect"), zap.String("peer", addr), zap.Error(err))
 			return err
 		}
 
+		if len(dialoutArgs.Errors) > 0 {
+			dp.metrics["errorsTotal"].Inc()
+			m.logger.Warn("cisco.mdt.dialout", zap.String("peer", addr), zap.String("error", dialoutArgs.Errors))
+		}
+
 		if dialoutArgs.TotalSize == 0 {
-			m.dataChan <- dialoutArgs.Data
+			m.send(dp, dialoutArgs.Data)
 			continue
 		}
 
-		buf.Write(dialoutArgs.Data)
-		if int32(buf.Len()) >= dialoutArgs.TotalSize {
-			m.dataChan <- dialoutArgs.Data
-			buf.Reset()
+		if int(dialoutArgs.TotalSize) > chunks.maxSize {
+			chunks.remove(dialoutArgs.ReqId)
+			dp.metrics["chunksDropsTotal"].Inc()
+			m.logger.Warn("cisco.mdt.dialout", zap.String("peer", addr), zap.String("error", "message size exceeded"),
+				zap.Int32("size", dialoutArgs.TotalSize))
+			continue
+		}
+
+		data, evicted := chunks.add(dialoutArgs.ReqId, dialoutArgs.Data, int(dialoutArgs.TotalSize))
+		for i := 0; i < evicted; i++ {
+			dp.metrics["chunksDropsTotal"].Inc()
+		}
+
+		if data != nil {
+			m.send(dp, data)
+		}
+	}
+}
+
+func newChunks(maxSize int) *chunks {
+	return &chunks{
+		bufs:    make(map[int64]*bytes.Buffer),
+		maxSize: maxSize,
+	}
+}
+
+// add appends the data to its message and returns the message once it's
+// completed along with the number of the evicted incomplete messages.
+func (c *chunks) add(reqID int64, data []byte, totalSize int) ([]byte, int) {
+	var evicted int
+
+	buf, ok := c.bufs[reqID]
+	if !ok {
+		buf = new(bytes.Buffer)
+		c.bufs[reqID] = buf
+		c.reqs = append(c.reqs, reqID)
+	}
+
+	buf.Write(data)
+	c.size += len(data)
+
+	if buf.Len() >= totalSize {
+		c.remove(reqID)
+		return buf.Bytes(), evicted
+	}
+
+	for len(c.reqs) > maxPendingReqs || c.size > c.maxSize {
+		c.remove(c.reqs[0])
+		evicted++
+	}
+
+	return nil, evicted
+}
+
+func (c *chunks) remove(reqID int64) {
+	buf, ok := c.bufs[reqID]
+	if !ok {
+		return
+	}
+
+	c.size -= buf.Len()
+	delete(c.bufs, reqID)
+
+	for i, id := range c.reqs {
+		if id == reqID {
+			c.reqs = append(c.reqs[:i], c.reqs[i+1:]...)
+			break
 		}
 	}
 }
 
+func (m *Dialout) send(dp *dialoutPeer, data []byte) {
+	dp.metrics["dataTotal"].Inc()
+
+	select {
+	case m.dataChan <- dialoutData{peer: dp, data: data}:
+	default:
+		dp.metrics["dropsTotal"].Inc()
+	}
+}
+
 func (m *Dialout) worker() {
 	var buf = new(bytes.Buffer)
 	for {
@@ -157,8 +343,9 @@ func (m *Dialout) worker() {
 				return
 			}
 
-			if err := m.datastore(buf, d); err != nil {
-				m.logger.Error("cisco.mdt.dialout", zap.Error(err))
+			if err := m.datastore(buf, d.data, d.peer); err != nil {
+				d.peer.metrics["errorsTotal"].Inc()
+				m.logger.Error("cisco.mdt.dialout", zap.String("peer", d.peer.host), zap.Error(err))
 			}
 
 		case <-m.ctx.Done():
@@ -167,31 +354,212 @@ func (m *Dialout) worker() {
 	}
 }
 
-func (m *Dialout) datastore(buf *bytes.Buffer, data []byte) error {
+// addPeer returns the connected device's peer, the metrics registers at the first
+// stream of the device. the unknown or unauthorized peers are rejected once the
+// client auth is enabled, the device is identified by the client certificate.
+func (m *Dialout) addPeer(addr string, state *tls.ConnectionState) (*dialoutPeer, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	host, err := m.identify(addr, state)
+	if err != nil {
+		if errors.Is(err, dialout.ErrUnknownPeer) {
+			m.metrics["unknownPeersTotal"].Inc()
+		} else {
+			m.metrics["unauthorizedPeersTotal"].Inc()
+		}
+
+		m.logger.Warn("cisco.mdt.dialout", zap.String("event", "reject"), zap.String("peer", addr), zap.Error(err))
+
+		return nil, err
+	}
+
+	if dp, ok := m.peers[host]; ok {
+		dp.refs++
+		return dp, nil
+	}
+
+	dp := newPeer(host)
+	dp.refs++
+	dp.labels = m.devices[host].Labels
+	m.peers[host] = dp
+
+	status.Register(status.Labels{"service": m.conf.Name, "peer": host}, dp.metrics)
+	m.metrics["sessionsCurrent"].Inc()
+
+	return dp, nil
+}
+
+// identify returns the device's host, the unknown peers are
+// accepted by their address if the client auth is not enabled.
+func (m *Dialout) identify(addr string, state *tls.ConnectionState) (string, error) {
+	if m.conf.ClientAuth != dialout.ClientAuthNone {
+		return dialout.Identify(state, addr, m.identities, m.hosts, m.conf.ClientAuth)
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	if h, ok := m.hosts[host]; ok {
+		host = h
+	}
+
+	return host, nil
+}
+
+func (m *Dialout) removePeer(dp *dialoutPeer) {
+	m.Lock()
+	defer m.Unlock()
+
+	dp.refs--
+	if dp.refs > 0 {
+		return
+	}
+
+	delete(m.peers, dp.host)
+
+	status.Unregister(status.Labels{"service": m.conf.Name, "peer": dp.host}, dp.metrics)
+	m.metrics["sessionsCurrent"].Dec()
+}
+
+func newPeer(host string) *dialoutPeer {
+	var metrics = make(map[string]status.Metrics)
+
+	metrics["dataTotal"] = status.NewCounter("cisco_mdt_dialout_data_total", "")
+	metrics["dropsTotal"] = status.NewCounter("cisco_mdt_dialout_drops_total", "")
+	metrics["errorsTotal"] = status.NewCounter("cisco_mdt_dialout_errors_total", "")
+	metrics["chunksDropsTotal"] = status.NewCounter("cisco_mdt_dialout_chunks_drops_total", "")
+
+	return &dialoutPeer{
+		host:    host,
+		metrics: metrics,
+	}
+}
+
+// getTLSConfig returns the reloadable TLS server config which
+// verifies the client certificates once the client auth is enabled.
+func (m *Dialout) getTLSConfig() (*tls.Config, error) {
+	clientAuth, err := dialout.GetTLSClientAuth(m.conf.ClientAuth, m.conf.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, closeTLS, err := secret.GetTLSServerReloadConfig(m.ctx, m.conf.Name, &m.conf.TLSConfig, clientAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	m.closeTLS = closeTLS
+
+	return tlsConfig, nil
+}
+
+func (m *Dialout) getMaxMsgSize() int {
+	if m.conf.MaxMsgSize > 0 {
+		return m.conf.MaxMsgSize
+	}
+
+	return defaultMaxMsgSize
+}
+
+func (m *Dialout) datastore(buf *bytes.Buffer, data []byte, dp *dialoutPeer) error {
+	if isJSON(data) {
+		d, err := decodeJSON(data)
+		if err != nil {
+			return err
+		}
+
+		m.rowsHandler(d, dp)
+
+		return nil
+	}
+
 	tm := &mdt.Telemetry{}
 	err := proto.Unmarshal(data, tm)
 	if err != nil {
 		return err
 	}
 
-	m.handler(buf, tm)
+	if tm.DataGpb != nil {
+		d, err := decodeCompact(tm)
+		if err != nil {
+			return err
+		}
+
+		m.rowsHandler(d, dp)
+
+		return nil
+	}
+
+	m.handler(buf, tm, dp)
 
 	return nil
 }
 
-func (m *Dialout) handler(buf *bytes.Buffer, tm *mdt.Telemetry) {
+// rowsHandler handles the compact GPB and JSON rows.
+func (m *Dialout) rowsHandler(d *mdtData, dp *dialoutPeer) {
+	output, err := m.getOutput(dp.host, d.subscription)
+	if err != nil {
+		dp.metrics["dropsTotal"].Inc()
+		m.logger.Error("cisco.mdt.dialout", zap.String("peer", dp.host), zap.Error(err))
+		return
+	}
+
+	for _, r := range d.rows {
+		labels := map[string]string{
+			"subscriptionId": d.subscription,
+			"nodeId":         d.nodeID,
+			"path":           d.path,
+		}
+
+		for k, v := range dp.labels {
+			labels[k] = v
+		}
+
+		for k, v := range r.keys {
+			labels[k] = v
+		}
+
+		for key, value := range r.kv {
+			dataStore := telemetry.DataStore{
+				"prefix":    d.path,
+				"labels":    labels,
+				"timestamp": r.timestamp,
+				"system_id": d.nodeID,
+				"key":       key,
+				"value":     value,
+			}
+
+			select {
+			case m.outChan <- telemetry.ExtDataStore{
+				DS:     dataStore,
+				Output: output,
+			}:
+			default:
+				dp.metrics["dropsTotal"].Inc()
+				m.logger.Warn("cisco.mdt.dialout", zap.String("peer", dp.host), zap.String("error", "dataset drop"))
+			}
+		}
+	}
+}
+
+func (m *Dialout) handler(buf *bytes.Buffer, tm *mdt.Telemetry, dp *dialoutPeer) {
 	var (
 		prefix, output string
 		timestamp      uint64
 		err            error
 	)
 
-	for _, gpbkv := range tm.DataGpbkv {
-		output, err = m.getOutput(tm.GetSubscriptionIdStr())
-		if err != nil {
-			m.logger.Error("cisco.mdt.dialout", zap.Error(err))
-		}
+	output, err = m.getOutput(dp.host, tm.GetSubscriptionIdStr())
+	if err != nil {
+		dp.metrics["dropsTotal"].Inc()
+		m.logger.Error("cisco.mdt.dialout", zap.String("peer", dp.host), zap.Error(err))
+		return
+	}
 
+	for _, gpbkv := range tm.DataGpbkv {
 		timestamp = getTimestamp(gpbkv.Timestamp, tm.MsgTimestamp)
 
 		labels := map[string]string{
@@ -200,6 +568,10 @@ func (m *Dialout) handler(buf *bytes.Buffer, tm *mdt.Telemetry) {
 			"path":           tm.GetEncodingPath(),
 		}
 
+		for k, v := range dp.labels {
+			labels[k] = v
+		}
+
 		prefix = tm.GetEncodingPath()
 
 		var key, content *mdt.TelemetryField
@@ -241,8 +613,8 @@ func (m *Dialout) handler(buf *bytes.Buffer, tm *mdt.Telemetry) {
 				Output: output,
 			}:
 			default:
-				m.metrics["dropsTotal"].Inc()
-				m.logger.Warn("cisco.mdt.dialout", zap.String("error", "dataset drop"))
+				dp.metrics["dropsTotal"].Inc()
+				m.logger.Warn("cisco.mdt.dialout", zap.String("peer", dp.host), zap.String("error", "dataset drop"))
 			}
 		}
 
@@ -250,16 +622,46 @@ func (m *Dialout) handler(buf *bytes.Buffer, tm *mdt.Telemetry) {
 	}
 }
 
-func (m *Dialout) getOutput(sub string) (string, error) {
+// getOutput returns the output of the subscription, the peer's
+// sensors take precedence over the global default output and sensors.
+func (m *Dialout) getOutput(host, sub string) (string, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	if output, ok := m.peerOutput[host][sub]; ok {
+		return output, nil
+	}
+
 	if m.cfg.Global().Dialout.DefaultOutput != "" {
 		return m.cfg.Global().Dialout.DefaultOutput, nil
 	}
 
-	m.RLock()
-	defer m.RUnlock()
 	if output, ok := m.pathOutput[sub]; ok {
 		return output, nil
 	}
 
 	return "", errors.New("output not found")
 }
+
+// getOutputs returns the global subscription to output, the devices'
+// subscription to output and the resolved peer address to host.
+func getOutputs(cfg config.Config) (map[string]string, map[string]map[string]string, map[string]string, map[string]config.Device) {
+	var (
+		pathOutput = make(map[string]string)
+		peerOutput = make(map[string]map[string]string)
+	)
+
+	for _, sensor := range cfg.Sensors() {
+		pathOutput[sensor.Subscription] = sensor.Output
+	}
+
+	devices, hosts := dialout.GetDevices(cfg.Devices(), dialoutService)
+	for host, device := range devices {
+		peerOutput[host] = make(map[string]string)
+		for _, sensor := range device.Sensors[dialoutService] {
+			peerOutput[host][sensor.Subscription] = sensor.Output
+		}
+	}
+
+	return pathOutput, peerOutput, hosts, devices
+}
@@ -3,6 +3,9 @@ package mdt
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"testing"
 	"time"
 
@@ -23,6 +26,7 @@ func TestDialoutStart(t *testing.T) {
 	ch := make(telemetry.ExtDSChan, 2)
 
 	cfg.Global().Dialout = config.Dialout{
+		DefaultOutput: "console::stdout",
 		Services: map[string]config.DialoutService{
 			"cisco.mdt": {
 				Addr:    "127.0.0.1:50051",
@@ -31,8 +35,9 @@ func TestDialoutStart(t *testing.T) {
 		},
 	}
 
-	d := NewDialout(ctx, cfg, ch)
+	d := NewDialout(ctx, cfg, cfg.Global().Dialout.Services["cisco.mdt"], ch)
 	go d.Start()
+	defer d.Stop()
 	time.Sleep(time.Second)
 
 	conn, err := grpc.DialContext(ctx, "127.0.0.1:50051", grpc.WithInsecure())
@@ -64,7 +69,7 @@ func TestDialoutHandler(t *testing.T) {
 	}
 
 	tm := mock.MDTInterfaceII()
-	m.handler(buf, tm)
+	m.handler(buf, tm, newPeer("127.0.0.1"))
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
@@ -130,7 +135,171 @@ func TestUpdate(t *testing.T) {
 		{Subscription: "Sub2", Output: "test"},
 	}
 
+	cfg.MDevices = []config.Device{
+		{
+			DeviceConfig: config.DeviceConfig{Host: "127.0.0.1"},
+			Sensors: map[string][]*config.Sensor{
+				"cisco.mdt.dialout": {{Service: "cisco.mdt.dialout", Subscription: "Sub2", Output: "kafka1::core"}},
+			},
+		},
+	}
+
 	m.Update()
 
 	assert.Equal(t, map[string]string{"Sub2": "test"}, m.pathOutput)
+	assert.Equal(t, map[string]map[string]string{"127.0.0.1": {"Sub2": "kafka1::core"}}, m.peerOutput)
+	assert.Equal(t, "127.0.0.1", m.hosts["127.0.0.1"])
+
+	output, err := m.getOutput("127.0.0.1", "Sub2")
+	assert.NoError(t, err)
+	assert.Equal(t, "kafka1::core", output)
+
+	output, err = m.getOutput("127.0.0.2", "Sub2")
+	assert.NoError(t, err)
+	assert.Equal(t, "test", output)
+
+	_, err = m.getOutput("127.0.0.2", "Sub3")
+	assert.Error(t, err)
+}
+
+func TestDialoutChunks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := config.NewMockConfig()
+	ch := make(telemetry.ExtDSChan, 10)
+
+	cfg.MGlobal.Dialout.DefaultOutput = "console::stdout"
+
+	conf := config.DialoutService{Addr: "127.0.0.1:50052", Workers: 1, MaxMsgSize: 1 << 20}
+	d := NewDialout(ctx, cfg, conf, ch).(*Dialout)
+	err := d.Start()
+	assert.NoError(t, err)
+	defer d.Stop()
+
+	conn, err := grpc.DialContext(ctx, "127.0.0.1:50052", grpc.WithInsecure())
+	assert.NoError(t, err)
+	mdtDialoutClient := dialout.NewGRPCMdtDialoutClient(conn)
+	mdtDialout, err := mdtDialoutClient.MdtDialout(ctx)
+	assert.NoError(t, err)
+
+	b, err := proto.Marshal(mock.MDTInterfaceII())
+	assert.NoError(t, err)
+
+	// interleaved chunks of two requests
+	half := len(b) / 2
+	mdtDialout.Send(&dialout.MdtDialoutArgs{ReqId: 1, Data: b[:half], TotalSize: int32(len(b))})
+	mdtDialout.Send(&dialout.MdtDialoutArgs{ReqId: 2, Data: b[:half], TotalSize: int32(len(b))})
+	mdtDialout.Send(&dialout.MdtDialoutArgs{ReqId: 1, Data: b[half:], TotalSize: int32(len(b))})
+	mdtDialout.Send(&dialout.MdtDialoutArgs{ReqId: 2, Data: b[half:], TotalSize: int32(len(b))})
+
+	// exceeded the maximum message size
+	mdtDialout.Send(&dialout.MdtDialoutArgs{ReqId: 3, Data: b, TotalSize: 2 << 20})
+
+	for i := 0; i < 8; i++ {
+		select {
+		case r := <-ch:
+			assert.Equal(t, "ios", r.DS["system_id"])
+		case <-time.After(time.Second * 2):
+			t.Fatal("timeout")
+		}
+	}
+
+	d.RLock()
+	dp := d.peers["127.0.0.1"]
+	d.RUnlock()
+
+	if assert.NotNil(t, dp) {
+		assert.Equal(t, uint64(2), dp.metrics["dataTotal"].Get())
+		assert.Eventually(t, func() bool {
+			return dp.metrics["chunksDropsTotal"].Get() == 1
+		}, time.Second, time.Millisecond*10)
+	}
+}
+
+func TestChunks(t *testing.T) {
+	c := newChunks(100)
+
+	data, evicted := c.add(1, make([]byte, 30), 60)
+	assert.Nil(t, data)
+	assert.Equal(t, 0, evicted)
+
+	data, evicted = c.add(1, make([]byte, 30), 60)
+	assert.Len(t, data, 60)
+	assert.Equal(t, 0, evicted)
+	assert.Equal(t, 0, c.size)
+
+	// the oldest incomplete message is evicted once the buffered size exceeded
+	c.add(2, make([]byte, 50), 80)
+	_, evicted = c.add(3, make([]byte, 60), 80)
+	assert.Equal(t, 1, evicted)
+	assert.Equal(t, []int64{3}, c.reqs)
+	assert.Equal(t, 60, c.size)
+
+	// the oldest incomplete message is evicted once the incomplete messages exceeded
+	c = newChunks(1 << 20)
+	for i := 0; i < maxPendingReqs; i++ {
+		c.add(int64(i), []byte{1}, 2)
+	}
+
+	_, evicted = c.add(maxPendingReqs, []byte{1}, 2)
+	assert.Equal(t, 1, evicted)
+	assert.Len(t, c.bufs, maxPendingReqs)
+	assert.NotContains(t, c.bufs, int64(0))
+}
+
+func TestDialoutClientAuth(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := config.NewMockConfig()
+	ch := make(telemetry.ExtDSChan, 10)
+
+	cfg.MDevices = []config.Device{
+		{
+			DeviceConfig: config.DeviceConfig{
+				Host:       "127.0.0.2",
+				Labels:     map[string]string{"site": "lax"},
+				Identities: []string{"core1.lax.panoptes.io"},
+			},
+			Sensors: map[string][]*config.Sensor{
+				"cisco.mdt.dialout": {{Service: "cisco.mdt.dialout", Subscription: "Sub3", Output: "console::stdout"}},
+			},
+		},
+	}
+
+	m := NewDialout(ctx, cfg, config.DialoutService{ClientAuth: "require"}, ch).(*Dialout)
+	defer m.Stop()
+
+	// the client certificate is required
+	_, err := m.addPeer("127.0.0.2:50000", nil)
+	assert.Error(t, err)
+	assert.Equal(t, uint64(1), m.metrics["unauthorizedPeersTotal"].Get())
+
+	state := &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "edge1.lax.panoptes.io"}}},
+	}
+
+	_, err = m.addPeer("127.0.0.2:50000", state)
+	assert.Error(t, err)
+	assert.Equal(t, uint64(1), m.metrics["unknownPeersTotal"].Get())
+
+	state.PeerCertificates[0].DNSNames = []string{"core1.lax.panoptes.io"}
+
+	dp, err := m.addPeer("127.0.0.3:50000", state)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer m.removePeer(dp)
+
+	assert.Equal(t, "127.0.0.2", dp.host)
+
+	// the device's labels are added to the data
+	m.handler(new(bytes.Buffer), mock.MDTInterfaceII(), dp)
+
+	select {
+	case r := <-ch:
+		assert.Equal(t, "console::stdout", r.Output)
+		assert.Equal(t, "lax", r.DS["labels"].(map[string]string)["site"])
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
 }
@@ -0,0 +1,296 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package mdt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// IOS-XR and NX-OS TCP/UDP dial-out header (12 bytes)
+// type(2) encap(2) header version(2) flags(2) length(4)
+const (
+	headerLen = 12
+
+	headerTypeData    = 1
+	headerEncapGPB    = 1
+	headerEncapJSON   = 2
+	headerVersion     = 1
+	headerFlagsNone   = 0
+	maxDatagramLength = 65535
+)
+
+const (
+	// readTimeout is the maximum time to complete the TLS handshake or to read a message.
+	readTimeout = 10 * time.Second
+	// idleTimeout is the maximum time between the messages of a TCP connection
+	// or a UDP peer, the idle UDP peers are removed.
+	idleTimeout = 10 * time.Minute
+)
+
+type udpPeer struct {
+	dp   *dialoutPeer
+	seen time.Time
+}
+
+type header struct {
+	msgType uint16
+	encap   uint16
+	version uint16
+	flags   uint16
+	length  uint32
+}
+
+func decodeHeader(b []byte) header {
+	return header{
+		msgType: binary.BigEndian.Uint16(b[0:2]),
+		encap:   binary.BigEndian.Uint16(b[2:4]),
+		version: binary.BigEndian.Uint16(b[4:6]),
+		flags:   binary.BigEndian.Uint16(b[6:8]),
+		length:  binary.BigEndian.Uint32(b[8:12]),
+	}
+}
+
+func (h header) validate(maxSize int) error {
+	if h.msgType != headerTypeData {
+		return fmt.Errorf("unsupported message type %d", h.msgType)
+	}
+
+	if h.encap != headerEncapGPB && h.encap != headerEncapJSON {
+		return fmt.Errorf("unsupported encapsulation %d", h.encap)
+	}
+
+	if h.version != headerVersion {
+		return fmt.Errorf("unsupported header version %d", h.version)
+	}
+
+	if h.flags != headerFlagsNone {
+		return fmt.Errorf("unsupported header flags %d", h.flags)
+	}
+
+	if int(h.length) > maxSize {
+		return fmt.Errorf("message size exceeded %d", h.length)
+	}
+
+	return nil
+}
+
+func (m *Dialout) startTCP() error {
+	ln, err := net.Listen("tcp", m.conf.Addr)
+	if err != nil {
+		return err
+	}
+
+	if m.conf.TLSConfig.Enabled {
+		tlsConfig, err := m.getTLSConfig()
+		if err != nil {
+			ln.Close()
+			return err
+		}
+
+		ln = tls.NewListener(ln, tlsConfig)
+	}
+
+	go func() {
+		<-m.ctx.Done()
+		ln.Close()
+	}()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				if m.ctx.Err() == nil {
+					m.logger.Error("cisco.mdt.dialout", zap.String("transport", "tcp"), zap.Error(err))
+				}
+				return
+			}
+
+			go m.serveTCP(conn)
+		}
+	}()
+
+	return nil
+}
+
+// serveTCP reads the framed messages from a device's connection.
+func (m *Dialout) serveTCP(conn net.Conn) {
+	var (
+		addr    = conn.RemoteAddr().String()
+		maxSize = m.getMaxMsgSize()
+		hdr     = make([]byte, headerLen)
+		reader  = bufio.NewReader(conn)
+	)
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	defer cancel()
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	var state *tls.ConnectionState
+
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn.SetDeadline(time.Now().Add(readTimeout))
+		if err := tlsConn.Handshake(); err != nil {
+			m.metrics["unauthorizedPeersTotal"].Inc()
+			m.logger.Warn("cisco.mdt.dialout", zap.String("event", "reject"), zap.String("peer", addr), zap.Error(err))
+			return
+		}
+		conn.SetDeadline(time.Time{})
+
+		s := tlsConn.ConnectionState()
+		state = &s
+	}
+
+	m.logger.Info("cisco.mdt.dialout", zap.String("event", "connect"), zap.String("transport", "tcp"), zap.String("peer", addr))
+
+	dp, err := m.addPeer(addr, state)
+	if err != nil {
+		return
+	}
+	defer m.removePeer(dp)
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(idleTimeout))
+		if _, err := io.ReadFull(reader, hdr); err != nil {
+			if err != io.EOF && m.ctx.Err() == nil {
+				m.logger.Warn("cisco.mdt.dialout", zap.String("event", "disconnect"), zap.String("peer", addr), zap.Error(err))
+			}
+			return
+		}
+
+		h := decodeHeader(hdr)
+		if err := h.validate(maxSize); err != nil {
+			// the stream can't be resynchronized once the header is invalid
+			dp.metrics["errorsTotal"].Inc()
+			m.logger.Error("cisco.mdt.dialout", zap.String("peer", addr), zap.Error(err))
+			return
+		}
+
+		conn.SetReadDeadline(time.Now().Add(readTimeout))
+		data := make([]byte, h.length)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			dp.metrics["errorsTotal"].Inc()
+			m.logger.Error("cisco.mdt.dialout", zap.String("peer", addr), zap.Error(err))
+			return
+		}
+
+		m.send(dp, data)
+	}
+}
+
+func (m *Dialout) startUDP() error {
+	conn, err := net.ListenPacket("udp", m.conf.Addr)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-m.ctx.Done()
+		conn.Close()
+	}()
+
+	go m.serveUDP(conn)
+
+	return nil
+}
+
+// serveUDP reads the datagrams, each datagram contains
+// the header and a whole message.
+func (m *Dialout) serveUDP(conn net.PacketConn) {
+	var (
+		maxSize   = m.getMaxMsgSize()
+		buf       = make([]byte, maxDatagramLength)
+		peers     = make(map[string]*udpPeer)
+		lastCheck = time.Now()
+	)
+
+	defer func() {
+		for _, p := range peers {
+			m.removePeer(p.dp)
+		}
+	}()
+
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if m.ctx.Err() == nil {
+				m.logger.Error("cisco.mdt.dialout", zap.String("transport", "udp"), zap.Error(err))
+			}
+			return
+		}
+
+		now := time.Now()
+		if now.Sub(lastCheck) > idleTimeout {
+			m.expireUDPPeers(peers, now)
+			lastCheck = now
+		}
+
+		host, _, _ := net.SplitHostPort(addr.String())
+		p, ok := peers[host]
+		if !ok {
+			// the client auth is not available for UDP
+			dp, err := m.addPeer(addr.String(), nil)
+			if err != nil {
+				continue
+			}
+
+			p = &udpPeer{dp: dp}
+			peers[host] = p
+		}
+
+		p.seen = now
+
+		if err := validateDatagram(buf[:n], maxSize); err != nil {
+			p.dp.metrics["errorsTotal"].Inc()
+			m.logger.Error("cisco.mdt.dialout", zap.String("peer", addr.String()), zap.Error(err))
+			continue
+		}
+
+		data := make([]byte, n-headerLen)
+		copy(data, buf[headerLen:n])
+
+		m.send(p.dp, data)
+	}
+}
+
+// expireUDPPeers removes the peers which they haven't sent
+// a datagram during the idle timeout e.g. spoofed sources.
+func (m *Dialout) expireUDPPeers(peers map[string]*udpPeer, now time.Time) {
+	for host, p := range peers {
+		if now.Sub(p.seen) > idleTimeout {
+			m.removePeer(p.dp)
+			delete(peers, host)
+		}
+	}
+}
+
+func validateDatagram(b []byte, maxSize int) error {
+	if len(b) < headerLen {
+		return errors.New("invalid datagram")
+	}
+
+	h := decodeHeader(b)
+	if err := h.validate(maxSize); err != nil {
+		return err
+	}
+
+	if int(h.length) != len(b)-headerLen {
+		return errors.New("invalid datagram length")
+	}
+
+	return nil
+}
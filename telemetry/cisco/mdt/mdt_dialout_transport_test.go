@@ -0,0 +1,158 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package mdt
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/telemetry"
+	"github.com/yahoo/panoptes-stream/telemetry/mock"
+)
+
+func frame(data []byte) []byte {
+	b := make([]byte, headerLen+len(data))
+	binary.BigEndian.PutUint16(b[0:2], headerTypeData)
+	binary.BigEndian.PutUint16(b[2:4], headerEncapGPB)
+	binary.BigEndian.PutUint16(b[4:6], headerVersion)
+	binary.BigEndian.PutUint16(b[6:8], headerFlagsNone)
+	binary.BigEndian.PutUint32(b[8:12], uint32(len(data)))
+	copy(b[headerLen:], data)
+
+	return b
+}
+
+func TestDialoutTCP(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := config.NewMockConfig()
+	ch := make(telemetry.ExtDSChan, 10)
+
+	cfg.MGlobal.Dialout.DefaultOutput = "console::stdout"
+
+	conf := config.DialoutService{Addr: "127.0.0.1:50053", Transport: "tcp", Workers: 1}
+	d := NewDialout(ctx, cfg, conf, ch)
+	err := d.Start()
+	assert.NoError(t, err)
+	defer d.Stop()
+
+	conn, err := net.Dial("tcp", "127.0.0.1:50053")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	b, err := proto.Marshal(mock.MDTInterfaceII())
+	assert.NoError(t, err)
+
+	// two messages in a single write
+	conn.Write(append(frame(b), frame(b)...))
+
+	for i := 0; i < 8; i++ {
+		select {
+		case r := <-ch:
+			assert.Equal(t, "ios", r.DS["system_id"])
+			assert.Equal(t, "openconfig-interfaces:interfaces/interface", r.DS["prefix"])
+		case <-time.After(time.Second * 2):
+			t.Fatal("timeout")
+		}
+	}
+}
+
+func TestDialoutUDP(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := config.NewMockConfig()
+	ch := make(telemetry.ExtDSChan, 10)
+
+	cfg.MGlobal.Dialout.DefaultOutput = "console::stdout"
+
+	conf := config.DialoutService{Addr: "127.0.0.1:50054", Transport: "udp", Workers: 1}
+	d := NewDialout(ctx, cfg, conf, ch).(*Dialout)
+	err := d.Start()
+	assert.NoError(t, err)
+	defer d.Stop()
+
+	conn, err := net.Dial("udp", "127.0.0.1:50054")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	b, err := proto.Marshal(mock.MDTInterfaceII())
+	assert.NoError(t, err)
+
+	// truncated datagram
+	conn.Write(frame(b)[:headerLen+10])
+	conn.Write(frame(b))
+
+	for i := 0; i < 4; i++ {
+		select {
+		case r := <-ch:
+			assert.Equal(t, "ios", r.DS["system_id"])
+		case <-time.After(time.Second * 2):
+			t.Fatal("timeout")
+		}
+	}
+
+	d.RLock()
+	dp := d.peers["127.0.0.1"]
+	d.RUnlock()
+
+	if assert.NotNil(t, dp) {
+		assert.Equal(t, uint64(1), dp.metrics["errorsTotal"].Get())
+		assert.Equal(t, uint64(1), dp.metrics["dataTotal"].Get())
+	}
+}
+
+func TestDialoutTransportNotSupported(t *testing.T) {
+	cfg := config.NewMockConfig()
+	conf := config.DialoutService{Addr: "127.0.0.1:50055", Transport: "sctp"}
+	d := NewDialout(context.Background(), cfg, conf, nil)
+	err := d.Start()
+	assert.Error(t, err)
+	d.Stop()
+}
+
+func TestHeaderValidate(t *testing.T) {
+	h := decodeHeader(frame(make([]byte, 10)))
+	assert.NoError(t, h.validate(10))
+	assert.Error(t, h.validate(9))
+
+	h.encap = 5
+	assert.Error(t, h.validate(10))
+}
+
+func TestExpireUDPPeers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := config.NewMockConfig()
+
+	d := NewDialout(ctx, cfg, config.DialoutService{}, nil).(*Dialout)
+	defer d.Stop()
+
+	now := time.Now()
+	peers := make(map[string]*udpPeer)
+	for _, addr := range []string{"127.0.0.1:5000", "127.0.0.2:5000"} {
+		dp, err := d.addPeer(addr, nil)
+		assert.NoError(t, err)
+		peers[dp.host] = &udpPeer{dp: dp, seen: now}
+	}
+
+	peers["127.0.0.1"].seen = now.Add(-idleTimeout - time.Second)
+
+	d.expireUDPPeers(peers, now)
+
+	assert.Len(t, peers, 1)
+	assert.Contains(t, peers, "127.0.0.2")
+	assert.NotContains(t, d.peers, "127.0.0.1")
+	assert.Equal(t, uint64(1), d.metrics["sessionsCurrent"].Get())
+}
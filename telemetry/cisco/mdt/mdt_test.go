@@ -128,6 +128,34 @@ func TestMDTMockServer(t *testing.T) {
 	}
 }
 
+func TestEncodings(t *testing.T) {
+	cfg := config.NewMockConfig()
+
+	conn, err := grpc.Dial("127.0.0.1:50556", grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	sensors := []*config.Sensor{
+		{Subscription: "Sub1", Output: "console::stdout"},
+		{Subscription: "Sub2", Output: "console::stdout", Encoding: "gpbkv"},
+		{Subscription: "Sub3", Output: "console::stdout", Encoding: "gpb"},
+		{Subscription: "Sub4", Output: "console::stdout", Encoding: "json"},
+		{Subscription: "Sub5", Output: "console::stdout", Encoding: "xml"},
+	}
+
+	m := New(cfg.Logger(), conn, sensors, make(telemetry.ExtDSChan, 1)).(*MDT)
+	assert.Equal(t, map[int64][]string{
+		encodingGPBKV: {"Sub1", "Sub2"},
+		encodingGPB:   {"Sub3"},
+		encodingJSON:  {"Sub4"},
+	}, m.subscriptions)
+
+	_, ok := m.pathOutput["Sub5"]
+	assert.False(t, ok)
+}
+
 func TestGetKeyValue(t *testing.T) {
 	f := mdtTelemetry.TelemetryField_StringValue{StringValue: "GigabitEthernet0/0/0/0"}
 	r := getKeyValue(&mdtTelemetry.TelemetryField{ValueByType: &f})
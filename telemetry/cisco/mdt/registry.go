@@ -0,0 +1,442 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package mdt
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// schemas is the compact GPB descriptor registry
+var schemas = &registry{
+	messages: make(map[string]*message),
+	enums:    make(map[string]bool),
+	paths:    make(map[string]*schema),
+}
+
+type registry struct {
+	messages map[string]*message
+	enums    map[string]bool
+	paths    map[string]*schema
+
+	sync.RWMutex
+}
+
+// schema represents the keys and content messages of an encoding path
+type schema struct {
+	keys    *message
+	content *message
+}
+
+type message struct {
+	name   string
+	fields map[int]*field
+}
+
+type field struct {
+	name     string
+	kind     string
+	number   int
+	repeated bool
+	scope    string
+	message  *message
+}
+
+// LoadProtos loads the compact GPB schemas from the .proto files,
+// the paths can be files or directories (walks recursively).
+func LoadProtos(paths []string) error {
+	var files []string
+
+	for _, path := range paths {
+		err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !info.IsDir() && strings.HasSuffix(p, ".proto") {
+				files = append(files, p)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	schemas.Lock()
+	defer schemas.Unlock()
+
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		if err := schemas.parse(string(b)); err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+	}
+
+	return schemas.resolve()
+}
+
+// getSchema returns the schema of the encoding path e.g.
+// Cisco-IOS-XR-infra-statsd-oper:infra-statistics/interfaces/interface/latest/generic-counters
+// is bound to the cisco_ios_xr_infra_statsd_oper.infra_statistics.interfaces.interface.latest.generic_counters package.
+func getSchema(path string) (*schema, bool) {
+	schemas.RLock()
+	defer schemas.RUnlock()
+
+	s, ok := schemas.paths[packageName(path)]
+
+	return s, ok
+}
+
+func packageName(path string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-':
+			return '_'
+		case ':', '/':
+			return '.'
+		}
+		return unicode.ToLower(r)
+	}, strings.Trim(path, "/"))
+}
+
+// parse parses the messages and enums of a proto file. it supports the
+// subset which the compact GPB schemas use: syntax, package, messages
+// (nested), enums, oneofs, scalar/message/enum fields and the reserved
+// and option statements which are ignored. the imports, services,
+// extensions, maps, groups and aggregate option values are rejected.
+func (r *registry) parse(src string) error {
+	var (
+		pkg    string
+		tokens = tokenize(src)
+		scope  []string
+		msgs   []*message
+		err    error
+	)
+
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "syntax", "reserved":
+			i = skip(tokens, i, ";")
+		case "option":
+			if i, err = skipOption(tokens, i); err != nil {
+				return err
+			}
+		case "package":
+			if i+1 < len(tokens) {
+				pkg = tokens[i+1]
+			}
+			i = skip(tokens, i, ";")
+		case "import", "service", "extend", "extensions":
+			return fmt.Errorf("%s is not supported", tokens[i])
+		case "enum":
+			if i+1 < len(tokens) {
+				r.enums[fullName(pkg, scope, tokens[i+1])] = true
+			}
+			i = skipBlock(tokens, i)
+		case "message":
+			if i+1 >= len(tokens) {
+				return fmt.Errorf("invalid message")
+			}
+
+			m := &message{name: fullName(pkg, scope, tokens[i+1]), fields: make(map[int]*field)}
+			r.messages[m.name] = m
+
+			scope = append(scope, tokens[i+1])
+			msgs = append(msgs, m)
+			i = skip(tokens, i, "{")
+		case "oneof":
+			// the oneof fields belong to the enclosing message
+			i = skip(tokens, i, "{")
+			scope = append(scope, "")
+			msgs = append(msgs, msgs[len(msgs)-1])
+		case "}":
+			if len(scope) > 0 {
+				scope = scope[:len(scope)-1]
+				msgs = msgs[:len(msgs)-1]
+			}
+		case ";":
+		default:
+			if len(msgs) < 1 {
+				return fmt.Errorf("unexpected token %s", tokens[i])
+			}
+
+			f, next, err := parseField(tokens, i)
+			if err != nil {
+				return err
+			}
+
+			if f != nil {
+				f.scope = strings.Join(nonEmpty(append([]string{pkg}, scope...)), ".")
+				msgs[len(msgs)-1].fields[f.number] = f
+			}
+
+			i = next
+		}
+	}
+
+	if pkg != "" {
+		r.paths[pkg] = r.getPackageSchema(pkg)
+	}
+
+	return nil
+}
+
+// parseField parses [repeated|optional] type name = number [options];
+func parseField(tokens []string, i int) (*field, int, error) {
+	var f = &field{}
+
+	if tokens[i] == "repeated" {
+		f.repeated = true
+		i++
+	} else if tokens[i] == "optional" || tokens[i] == "required" {
+		i++
+	}
+
+	if i+3 >= len(tokens) {
+		return nil, i, fmt.Errorf("invalid field")
+	}
+
+	if tokens[i] == "map" || tokens[i] == "group" {
+		return nil, i, fmt.Errorf("%s field is not supported", tokens[i])
+	}
+
+	f.kind, f.name = tokens[i], tokens[i+1]
+	if tokens[i+2] != "=" {
+		return nil, i, fmt.Errorf("invalid field %s", f.name)
+	}
+
+	number, err := strconv.Atoi(tokens[i+3])
+	if err != nil {
+		return nil, i, fmt.Errorf("invalid field number %s", f.name)
+	}
+	f.number = number
+
+	next, err := skipOption(tokens, i+4)
+	if err != nil {
+		return nil, next, err
+	}
+
+	return f, next, nil
+}
+
+// skipOption skips to the end of an option statement or the field
+// options, the aggregate option values e.g. { a: 1 } are rejected.
+func skipOption(tokens []string, i int) (int, error) {
+	for ; i < len(tokens); i++ {
+		switch tokens[i] {
+		case ";":
+			return i, nil
+		case "{":
+			return i, fmt.Errorf("aggregate option value is not supported")
+		}
+	}
+
+	return i, nil
+}
+
+// resolve binds the fields to their message types.
+func (r *registry) resolve() error {
+	for _, m := range r.messages {
+		for _, f := range m.fields {
+			if isScalar(f.kind) || f.message != nil {
+				continue
+			}
+
+			name, ok := r.lookup(f.scope, f.kind)
+			if !ok {
+				return fmt.Errorf("type %s not found", f.kind)
+			}
+
+			if r.enums[name] {
+				f.kind = "enum"
+				continue
+			}
+
+			f.message = r.messages[name]
+		}
+	}
+
+	return nil
+}
+
+func (r *registry) lookup(scope, kind string) (string, bool) {
+	if strings.HasPrefix(kind, ".") {
+		kind = kind[1:]
+		return kind, r.messages[kind] != nil || r.enums[kind]
+	}
+
+	for {
+		name := kind
+		if scope != "" {
+			name = scope + "." + kind
+		}
+
+		if r.messages[name] != nil || r.enums[name] {
+			return name, true
+		}
+
+		if scope == "" {
+			return "", false
+		}
+
+		if i := strings.LastIndex(scope, "."); i > -1 {
+			scope = scope[:i]
+		} else {
+			scope = ""
+		}
+	}
+}
+
+// getPackageSchema returns the schema of a package, the keys message
+// has _KEYS suffix and the content message is the same without suffix.
+func (r *registry) getPackageSchema(pkg string) *schema {
+	var (
+		s   = &schema{}
+		top []*message
+	)
+
+	for name, m := range r.messages {
+		if !strings.HasPrefix(name, pkg+".") || strings.Contains(name[len(pkg)+1:], ".") {
+			continue
+		}
+
+		top = append(top, m)
+
+		if strings.HasSuffix(name, "_KEYS") {
+			s.keys = m
+			s.content = r.messages[strings.TrimSuffix(name, "_KEYS")]
+		}
+	}
+
+	if s.content == nil && len(top) == 1 {
+		s.content = top[0]
+	}
+
+	return s
+}
+
+func fullName(pkg string, scope []string, name string) string {
+	return strings.Join(nonEmpty(append(append([]string{pkg}, scope...), name)), ".")
+}
+
+func nonEmpty(s []string) []string {
+	var r []string
+	for _, v := range s {
+		if v != "" {
+			r = append(r, v)
+		}
+	}
+
+	return r
+}
+
+func isScalar(kind string) bool {
+	switch kind {
+	case "double", "float", "int32", "int64", "uint32", "uint64", "sint32", "sint64",
+		"fixed32", "fixed64", "sfixed32", "sfixed64", "bool", "string", "bytes", "enum":
+		return true
+	}
+
+	return false
+}
+
+func skip(tokens []string, i int, token string) int {
+	for ; i < len(tokens); i++ {
+		if tokens[i] == token {
+			return i
+		}
+	}
+
+	return i
+}
+
+func skipBlock(tokens []string, i int) int {
+	var depth int
+
+	for ; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return i
+}
+
+// tokenize splits the proto source to identifiers, numbers,
+// strings and symbols without the comments.
+func tokenize(src string) []string {
+	var tokens []string
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			tokens = append(tokens, src[i:minInt(j+1, len(src))])
+			i = j + 1
+		case isIdent(c) || c == '.':
+			j := i
+			for j < len(src) && (isIdent(src[j]) || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, src[i:j])
+			i = j
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+
+	return tokens
+}
+
+func isIdent(c byte) bool {
+	return c == '_' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
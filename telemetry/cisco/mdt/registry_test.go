@@ -0,0 +1,132 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package mdt
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var genericCountersProto = `
+syntax = "proto3";
+
+// generic counters
+package cisco_ios_xr_infra_statsd_oper.infra_statistics.interfaces.interface.latest.generic_counters;
+
+option go_package = "generic_counters";
+
+message ifstatsbag_generic_KEYS {
+    string interface_name = 1;
+}
+
+message ifstatsbag_generic {
+    uint64 packets_received = 1;
+    uint64 bytes_received = 2;
+    sint32 delta = 3;
+    rate_info rate = 4;
+    repeated uint32 queues = 5;
+    state_type state = 6;
+    oneof value {
+        string text = 7;
+    }
+
+    message rate_info {
+        double input = 1;
+    }
+
+    /* interface state */
+    enum state_type {
+        up = 0;
+        down = 1;
+    }
+}
+`
+
+func loadTestProtos(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mdt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "generic_counters.proto"), []byte(genericCountersProto), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = LoadProtos([]string{dir})
+	assert.NoError(t, err)
+}
+
+func TestLoadProtos(t *testing.T) {
+	loadTestProtos(t)
+
+	s, ok := getSchema("Cisco-IOS-XR-infra-statsd-oper:infra-statistics/interfaces/interface/latest/generic-counters")
+	assert.True(t, ok)
+	assert.NotNil(t, s.keys)
+	assert.NotNil(t, s.content)
+
+	assert.Equal(t, "interface_name", s.keys.fields[1].name)
+	assert.Equal(t, "uint64", s.content.fields[1].kind)
+	assert.True(t, s.content.fields[5].repeated)
+	assert.Equal(t, "enum", s.content.fields[6].kind)
+	assert.Equal(t, "text", s.content.fields[7].name)
+	assert.Equal(t, "double", s.content.fields[4].message.fields[1].kind)
+
+	err := LoadProtos([]string{"/not/exist"})
+	assert.Error(t, err)
+}
+
+func TestPackageName(t *testing.T) {
+	assert.Equal(t, "openconfig_interfaces.interfaces.interface", packageName("openconfig-interfaces:interfaces/interface"))
+	assert.Equal(t, "cisco_ios_xr_ip_bgp_oper.bgp.instances", packageName("Cisco-IOS-XR-ip-bgp-oper:bgp/instances/"))
+}
+
+func TestParseNestedOneof(t *testing.T) {
+	r := &registry{messages: make(map[string]*message), enums: make(map[string]bool), paths: make(map[string]*schema)}
+
+	err := r.parse(`
+syntax = "proto3";
+package test;
+message a {
+    message b {
+        oneof value {
+            string text = 1 [deprecated = true];
+            uint64 number = 2;
+        }
+        option deprecated = true;
+    }
+    b inner = 1;
+}
+`)
+	assert.NoError(t, err)
+	assert.NoError(t, r.resolve())
+
+	assert.Equal(t, "text", r.messages["test.a.b"].fields[1].name)
+	assert.Equal(t, "uint64", r.messages["test.a.b"].fields[2].kind)
+	assert.Equal(t, r.messages["test.a.b"], r.messages["test.a"].fields[1].message)
+}
+
+func TestParseUnsupported(t *testing.T) {
+	testCases := map[string]string{
+		"import":     `import "other.proto";`,
+		"service":    `service s { rpc get(a) returns (a); }`,
+		"extend":     `extend a { string b = 100; }`,
+		"extensions": `message a { extensions 100 to 199; }`,
+		"map":        `message a { map<string, uint64> b = 1; }`,
+		"group":      `message a { repeated group b = 1 { string c = 2; } }`,
+		"aggregate":  `message a { string b = 1 [(c) = { d: 1 }]; }`,
+		"option":     `option (a) = { b: 1 };`,
+	}
+
+	for name, src := range testCases {
+		r := &registry{messages: make(map[string]*message), enums: make(map[string]bool), paths: make(map[string]*schema)}
+		err := r.parse("syntax = \"proto3\";\npackage test;\n" + src)
+		assert.Error(t, err, name)
+	}
+}
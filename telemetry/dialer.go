@@ -0,0 +1,284 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package telemetry
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"golang.org/x/net/proxy"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/secret"
+)
+
+// ContextDialer represents a dialer which connects to
+// the device address through a proxy or a jump host.
+type ContextDialer func(ctx context.Context, addr string) (net.Conn, error)
+
+type dialerCredentials struct {
+	username string
+	password string
+	key      []byte
+}
+
+// NewDialer returns a context dialer based on the dialer type: socks5, http (CONNECT) or ssh.
+// the username and the key file can be a remote secret e.g. __vault::path, the username secret
+// is a username and password pair and the key file secret holds the private key as key.
+func NewDialer(cfg config.Dialer) (ContextDialer, error) {
+	if cfg.Addr == "" {
+		return nil, errors.New("dialer address not provided")
+	}
+
+	creds, err := getDialerCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(cfg.Type) {
+	case "socks5":
+		return newSOCKS5Dialer(cfg, creds)
+	case "http":
+		return newHTTPConnectDialer(cfg, creds), nil
+	case "ssh":
+		return newSSHDialer(cfg, creds)
+	}
+
+	return nil, fmt.Errorf("dialer type %s not supported", cfg.Type)
+}
+
+func getDialerCredentials(cfg config.Dialer) (dialerCredentials, error) {
+	creds := dialerCredentials{username: cfg.Username, password: cfg.Password}
+
+	if sType, path, ok := secret.ParseRemoteSecretInfo(cfg.Username); ok {
+		secrets, err := secret.GetCredentials(sType, path)
+		if err != nil {
+			return creds, err
+		}
+
+		creds.username = ""
+		for u, p := range secrets {
+			creds.username, creds.password = u, p
+			break
+		}
+
+		if creds.username == "" {
+			return creds, errors.New("dialer credentials are not available at remote host")
+		}
+	}
+
+	if cfg.KeyFile == "" {
+		return creds, nil
+	}
+
+	if sType, path, ok := secret.ParseRemoteSecretInfo(cfg.KeyFile); ok {
+		secrets, err := secret.GetCredentials(sType, path)
+		if err != nil {
+			return creds, err
+		}
+
+		key, ok := secrets["key"]
+		if !ok {
+			return creds, errors.New("dialer key is not available at remote host")
+		}
+
+		creds.key = []byte(key)
+
+		return creds, nil
+	}
+
+	key, err := ioutil.ReadFile(cfg.KeyFile)
+	if err != nil {
+		return creds, err
+	}
+
+	creds.key = key
+
+	return creds, nil
+}
+
+func newSOCKS5Dialer(cfg config.Dialer, creds dialerCredentials) (ContextDialer, error) {
+	var auth *proxy.Auth
+
+	if creds.username != "" {
+		auth = &proxy.Auth{User: creds.username, Password: creds.password}
+	}
+
+	d, err := proxy.SOCKS5("tcp", cfg.Addr, auth, &net.Dialer{Timeout: getDialerTimeout(cfg)})
+	if err != nil {
+		return nil, err
+	}
+
+	cd, ok := d.(proxy.ContextDialer)
+	if !ok {
+		return nil, errors.New("socks5 dialer doesn't support context")
+	}
+
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		return cd.DialContext(ctx, "tcp", addr)
+	}, nil
+}
+
+func newHTTPConnectDialer(cfg config.Dialer, creds dialerCredentials) ContextDialer {
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		d := net.Dialer{Timeout: getDialerTimeout(cfg)}
+		conn, err := d.DialContext(ctx, "tcp", cfg.Addr)
+		if err != nil {
+			return nil, err
+		}
+
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+			defer conn.SetDeadline(time.Time{})
+		}
+
+		req := &http.Request{
+			Method: http.MethodConnect,
+			URL:    &url.URL{Opaque: addr},
+			Host:   addr,
+			Header: make(http.Header),
+		}
+
+		if creds.username != "" {
+			auth := base64.StdEncoding.EncodeToString([]byte(creds.username + ":" + creds.password))
+			req.Header.Set("Proxy-Authorization", "Basic "+auth)
+		}
+
+		if err := req.Write(conn); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		br := bufio.NewReader(conn)
+		resp, err := http.ReadResponse(br, req)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			conn.Close()
+			return nil, fmt.Errorf("http proxy connect failed: %s", resp.Status)
+		}
+
+		// the device may have sent data right after the proxy response
+		if br.Buffered() > 0 {
+			return &bufferedConn{Conn: conn, r: br}, nil
+		}
+
+		return conn, nil
+	}
+}
+
+func newSSHDialer(cfg config.Dialer, creds dialerCredentials) (ContextDialer, error) {
+	var (
+		auth            []ssh.AuthMethod
+		hostKeyCallback ssh.HostKeyCallback
+		err             error
+	)
+
+	if creds.key != nil {
+		signer, err := ssh.ParsePrivateKey(creds.key)
+		if err != nil {
+			return nil, err
+		}
+
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+
+	if creds.password != "" {
+		auth = append(auth, ssh.Password(creds.password))
+	}
+
+	switch {
+	case cfg.KnownHostsFile != "":
+		hostKeyCallback, err = knownhosts.New(cfg.KnownHostsFile)
+		if err != nil {
+			return nil, err
+		}
+	case cfg.InsecureIgnoreHostKey:
+		hostKeyCallback = ssh.InsecureIgnoreHostKey()
+	default:
+		return nil, errors.New("ssh known hosts file not provided")
+	}
+
+	sshConfig := &ssh.ClientConfig{
+		User:            creds.username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         getDialerTimeout(cfg),
+	}
+
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		d := net.Dialer{Timeout: sshConfig.Timeout}
+		conn, err := d.DialContext(ctx, "tcp", cfg.Addr)
+		if err != nil {
+			return nil, err
+		}
+
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+
+		c, chans, reqs, err := ssh.NewClientConn(conn, cfg.Addr, sshConfig)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		conn.SetDeadline(time.Time{})
+
+		client := ssh.NewClient(c, chans, reqs)
+		tunnel, err := client.Dial("tcp", addr)
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+
+		return &sshConn{Conn: tunnel, client: client}, nil
+	}, nil
+}
+
+func getDialerTimeout(cfg config.Dialer) time.Duration {
+	if cfg.Timeout > 0 {
+		return time.Duration(cfg.Timeout) * time.Second
+	}
+
+	return 5 * time.Second
+}
+
+// bufferedConn reads the buffered data before the connection.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+// sshConn closes the jump host connection once the tunnel closed.
+type sshConn struct {
+	net.Conn
+	client *ssh.Client
+}
+
+func (c *sshConn) Close() error {
+	err := c.Conn.Close()
+	c.client.Close()
+
+	return err
+}
@@ -0,0 +1,272 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package telemetry
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/yahoo/panoptes-stream/config"
+)
+
+func startEchoServer(t *testing.T, addr string) net.Listener {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	return ln
+}
+
+// startHTTPProxy starts a HTTP CONNECT proxy which requires basic authentication.
+func startHTTPProxy(t *testing.T, addr string) net.Listener {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				req, err := http.ReadRequest(bufio.NewReader(conn))
+				if err != nil {
+					return
+				}
+
+				// proxy-user:proxy-pass
+				if req.Header.Get("Proxy-Authorization") != "Basic cHJveHktdXNlcjpwcm94eS1wYXNz" {
+					io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n\r\n")
+					return
+				}
+
+				target, err := net.Dial("tcp", req.Host)
+				if err != nil {
+					io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\n\r\n")
+					return
+				}
+				defer target.Close()
+
+				io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+
+				go io.Copy(target, conn)
+				io.Copy(conn, target)
+			}()
+		}
+	}()
+
+	return ln
+}
+
+// startSOCKS5Proxy starts a SOCKS5 proxy without authentication (IPv4 connect only).
+func startSOCKS5Proxy(t *testing.T, addr string) net.Listener {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				buf := make([]byte, 262)
+				// version, methods
+				if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+					return
+				}
+				if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+					return
+				}
+				conn.Write([]byte{5, 0})
+
+				// version, cmd, rsv, atyp, ipv4, port
+				if _, err := io.ReadFull(conn, buf[:10]); err != nil {
+					return
+				}
+
+				ip := net.IP(buf[4:8])
+				port := binary.BigEndian.Uint16(buf[8:10])
+
+				target, err := net.Dial("tcp", net.JoinHostPort(ip.String(), strconv.Itoa(int(port))))
+				if err != nil {
+					conn.Write([]byte{5, 1, 0, 1, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				defer target.Close()
+
+				conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+
+				go io.Copy(target, conn)
+				io.Copy(conn, target)
+			}()
+		}
+	}()
+
+	return ln
+}
+
+func echo(t *testing.T, dialer ContextDialer, addr string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	conn, err := dialer(ctx, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	_, err = conn.Write([]byte("panoptes"))
+	assert.NoError(t, err)
+
+	buf := make([]byte, 8)
+	_, err = io.ReadFull(conn, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "panoptes", string(buf))
+}
+
+func TestHTTPConnectDialer(t *testing.T) {
+	echoLn := startEchoServer(t, "127.0.0.1:50062")
+	defer echoLn.Close()
+
+	proxyLn := startHTTPProxy(t, "127.0.0.1:50063")
+	defer proxyLn.Close()
+
+	dialer, err := NewDialer(config.Dialer{Type: "http", Addr: "127.0.0.1:50063", Username: "proxy-user", Password: "proxy-pass"})
+	assert.NoError(t, err)
+
+	echo(t, dialer, "127.0.0.1:50062")
+
+	// unauthorized
+	dialer, err = NewDialer(config.Dialer{Type: "http", Addr: "127.0.0.1:50063"})
+	assert.NoError(t, err)
+
+	_, err = dialer(context.Background(), "127.0.0.1:50062")
+	assert.Error(t, err)
+}
+
+func TestSOCKS5Dialer(t *testing.T) {
+	echoLn := startEchoServer(t, "127.0.0.1:50064")
+	defer echoLn.Close()
+
+	proxyLn := startSOCKS5Proxy(t, "127.0.0.1:50065")
+	defer proxyLn.Close()
+
+	dialer, err := NewDialer(config.Dialer{Type: "socks5", Addr: "127.0.0.1:50065"})
+	assert.NoError(t, err)
+
+	echo(t, dialer, "127.0.0.1:50064")
+}
+
+func TestNewDialerErrors(t *testing.T) {
+	_, err := NewDialer(config.Dialer{Type: "http"})
+	assert.Error(t, err)
+
+	_, err = NewDialer(config.Dialer{Type: "socks4", Addr: "127.0.0.1:1080"})
+	assert.Error(t, err)
+
+	// ssh host key verification is required
+	_, err = NewDialer(config.Dialer{Type: "ssh", Addr: "127.0.0.1:22", Username: "panoptes", Password: "secret"})
+	assert.Error(t, err)
+
+	_, err = NewDialer(config.Dialer{Type: "ssh", Addr: "127.0.0.1:22", Username: "panoptes", Password: "secret", InsecureIgnoreHostKey: true})
+	assert.NoError(t, err)
+
+	_, err = NewDialer(config.Dialer{Type: "ssh", Addr: "127.0.0.1:22", KeyFile: "/not/exist"})
+	assert.Error(t, err)
+}
+
+func TestGetDialer(t *testing.T) {
+	cfg := &config.MockConfig{MGlobal: &config.Global{}}
+	tm := &Telemetry{cfg: cfg, logger: zap.NewNop()}
+
+	device := &config.Device{}
+
+	dialer, err := tm.getDialer(device)
+	assert.NoError(t, err)
+	assert.Nil(t, dialer)
+
+	device.Dialer = "bastion"
+	_, err = tm.getDialer(device)
+	assert.Error(t, err)
+
+	cfg.MGlobal.Dialers = map[string]config.Dialer{"bastion": {Type: "socks5", Addr: "127.0.0.1:1080"}}
+	dialer, err = tm.getDialer(device)
+	assert.NoError(t, err)
+	assert.NotNil(t, dialer)
+
+	// global device options
+	device.Dialer = ""
+	cfg.MGlobal.DeviceOptions.Dialer = "bastion"
+	dialer, err = tm.getDialer(device)
+	assert.NoError(t, err)
+	assert.NotNil(t, dialer)
+}
+
+func TestGetDialerCache(t *testing.T) {
+	cfg := &config.MockConfig{MGlobal: &config.Global{}}
+	tm := &Telemetry{cfg: cfg, logger: zap.NewNop()}
+
+	device := &config.Device{}
+	device.Dialer = "bastion"
+	cfg.MGlobal.Dialers = map[string]config.Dialer{"bastion": {Type: "socks5", Addr: "127.0.0.1:1080"}}
+
+	_, err := tm.getDialer(device)
+	assert.NoError(t, err)
+	assert.Len(t, tm.dialers.dialers, 1)
+
+	cached := tm.dialers.dialers["bastion"]
+	_, err = tm.getDialer(device)
+	assert.NoError(t, err)
+	assert.Equal(t, cached.cfg, tm.dialers.dialers["bastion"].cfg)
+
+	// config changed
+	cfg.MGlobal.Dialers["bastion"] = config.Dialer{Type: "http", Addr: "127.0.0.1:3128"}
+	_, err = tm.getDialer(device)
+	assert.NoError(t, err)
+	assert.Equal(t, "http", tm.dialers.dialers["bastion"].cfg.Type)
+
+	tm.dialers.purge(cfg.MGlobal.Dialers)
+	assert.Len(t, tm.dialers.dialers, 1)
+
+	// dialer removed
+	tm.dialers.purge(map[string]config.Dialer{})
+	assert.Len(t, tm.dialers.dialers, 0)
+}
@@ -5,45 +5,139 @@ package dialout
 
 import (
 	"context"
+	"reflect"
+
+	"go.uber.org/zap"
 
 	"github.com/yahoo/panoptes-stream/config"
 	"github.com/yahoo/panoptes-stream/telemetry"
-	"github.com/yahoo/panoptes-stream/telemetry/cisco/mdt"
 )
 
 // Dialout represents dial-out mode for all telemetries.
 type Dialout struct {
-	cfg     config.Config
-	ctx     context.Context
-	outChan telemetry.ExtDSChan
-
-	mdtHandler *mdt.Dialout
+	cfg       config.Config
+	ctx       context.Context
+	logger    *zap.Logger
+	outChan   telemetry.ExtDSChan
+	registrar *Registrar
+	services  map[string]Service
+	confs     map[string]config.DialoutService
 }
 
 // New creates a new dialout instance.
-func New(ctx context.Context, cfg config.Config, outChan telemetry.ExtDSChan) *Dialout {
+func New(ctx context.Context, cfg config.Config, dr *Registrar, outChan telemetry.ExtDSChan) *Dialout {
 	return &Dialout{
-		cfg:     cfg,
-		ctx:     ctx,
-		outChan: outChan,
+		cfg:       cfg,
+		ctx:       ctx,
+		logger:    cfg.Logger(),
+		outChan:   outChan,
+		registrar: dr,
+		services:  make(map[string]Service),
+		confs:     make(map[string]config.DialoutService),
 	}
 }
 
 // Start starts available dialout telemetries.
 func (d *Dialout) Start() {
-	for service := range d.cfg.Global().Dialout.Services {
-		if service == "cisco.mdt" {
-			d.mdtHandler = mdt.NewDialout(d.ctx, d.cfg, d.outChan)
-			d.mdtHandler.Start()
-		}
+	for name, conf := range d.getServices() {
+		d.start(name, conf)
 	}
 }
 
-// Update updates dialout telemetries once configuration changed.
+// Update updates dialout telemetries once configuration changed
+// start/stop/restart dial-out services.
 func (d *Dialout) Update() {
-	for service := range d.cfg.Global().Dialout.Services {
-		if service == "cisco.mdt" {
-			d.mdtHandler.Update()
+	services := d.getServices()
+	delta := &struct {
+		add []string
+		del []string
+		mod []string
+	}{}
+
+	for name, conf := range services {
+		if _, ok := d.confs[name]; !ok {
+			delta.add = append(delta.add, name)
+			continue
+		}
+
+		if ok := reflect.DeepEqual(d.confs[name], conf); !ok {
+			delta.mod = append(delta.mod, name)
+			continue
+		}
+
+		if s, ok := d.services[name]; ok {
+			s.Update()
+		}
+	}
+
+	for name := range d.confs {
+		if _, ok := services[name]; !ok {
+			delta.del = append(delta.del, name)
 		}
 	}
+
+	for _, name := range delta.add {
+		d.start(name, services[name])
+	}
+
+	for _, name := range delta.del {
+		d.stop(name)
+	}
+
+	for _, name := range delta.mod {
+		d.stop(name)
+		d.start(name, services[name])
+	}
+}
+
+// start starts the service and records it once it started successfully
+// so the next update retries the failed service.
+func (d *Dialout) start(name string, conf config.DialoutService) {
+	new, ok := d.registrar.GetDialoutFactory(name)
+	if !ok {
+		d.logger.Error("dialout", zap.String("event", "service not exist"), zap.String("name", name))
+		return
+	}
+
+	s := new(d.ctx, d.cfg, conf, d.outChan)
+	if err := s.Start(); err != nil {
+		d.logger.Error("dialout", zap.String("event", "start"), zap.String("name", name), zap.Error(err))
+		s.Stop()
+		return
+	}
+
+	d.services[name] = s
+	d.confs[name] = conf
+}
+
+func (d *Dialout) stop(name string) {
+	if s, ok := d.services[name]; ok {
+		s.Stop()
+		d.logger.Info("dialout", zap.String("event", "stop"), zap.String("name", name))
+	}
+
+	delete(d.services, name)
+	delete(d.confs, name)
+}
+
+// getServices returns the configured dial-out services
+// the global TLS configuration applies once a service doesn't have it
+// and the name identifies the service instance e.g. cisco.mdt::edge.
+func (d *Dialout) getServices() map[string]config.DialoutService {
+	var (
+		dialout  = d.cfg.Global().Dialout
+		services = make(map[string]config.DialoutService)
+	)
+
+	for name, conf := range dialout.Services {
+		if !conf.TLSConfig.Enabled {
+			conf.TLSConfig = dialout.TLSConfig
+		}
+
+		conf.Name = name
+
+		services[name] = conf
+	}
+
+	return services
 }
@@ -0,0 +1,108 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package dialout
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+type testService struct {
+	conf    config.DialoutService
+	started bool
+	updated int
+	stopped bool
+}
+
+var testServices = make(map[string]*testService)
+
+func (s *testService) Update() { s.updated++ }
+func (s *testService) Stop()   { s.stopped = true }
+
+func (s *testService) Start() error {
+	if s.conf.Addr == "" {
+		return errors.New("address is empty")
+	}
+
+	s.started = true
+
+	return nil
+}
+
+func newTestService(ctx context.Context, cfg config.Config, conf config.DialoutService, outChan telemetry.ExtDSChan) Service {
+	s := &testService{conf: conf}
+	testServices[conf.Addr] = s
+	return s
+}
+
+func TestDialout(t *testing.T) {
+	cfg := config.NewMockConfig()
+	cfg.MGlobal.Dialout = config.Dialout{
+		TLSConfig: config.TLSConfig{Enabled: true, CertFile: "global.crt"},
+		Services: map[string]config.DialoutService{
+			"test":       {Addr: ":5001"},
+			"test::edge": {Addr: ":5002", TLSConfig: config.TLSConfig{Enabled: true, CertFile: "edge.crt"}},
+		},
+	}
+
+	registrar := NewRegistrar(cfg.Logger())
+	registrar.Register("test", "0.0.0", newTestService)
+
+	d := New(context.Background(), cfg, registrar, nil)
+	d.Start()
+
+	assert.Len(t, d.services, 2)
+	assert.True(t, testServices[":5001"].started)
+	assert.Equal(t, "global.crt", testServices[":5001"].conf.TLSConfig.CertFile)
+	assert.Equal(t, "edge.crt", testServices[":5002"].conf.TLSConfig.CertFile)
+	assert.Equal(t, "test::edge", testServices[":5002"].conf.Name)
+
+	// modify test, delete test::edge and add test::core
+	cfg.MGlobal.Dialout.Services = map[string]config.DialoutService{
+		"test":       {Addr: ":5003"},
+		"test::core": {Addr: ":5004"},
+	}
+
+	d.Update()
+
+	assert.Len(t, d.services, 2)
+	assert.True(t, testServices[":5001"].stopped)
+	assert.True(t, testServices[":5002"].stopped)
+	assert.True(t, testServices[":5003"].started)
+	assert.True(t, testServices[":5004"].started)
+
+	// unchanged
+	d.Update()
+
+	assert.Equal(t, 1, testServices[":5003"].updated)
+	assert.Equal(t, 1, testServices[":5004"].updated)
+	assert.False(t, testServices[":5003"].stopped)
+
+	// failed service is retried at the next update
+	cfg.MGlobal.Dialout.Services["test::fail"] = config.DialoutService{}
+
+	d.Update()
+
+	assert.Len(t, d.services, 2)
+	assert.NotContains(t, d.confs, "test::fail")
+	assert.True(t, testServices[""].stopped)
+
+	failed := testServices[""]
+	d.Update()
+
+	assert.NotSame(t, failed, testServices[""])
+
+	cfg.MGlobal.Dialout.Services["test::fail"] = config.DialoutService{Addr: ":5005"}
+
+	d.Update()
+
+	assert.Len(t, d.services, 3)
+	assert.True(t, testServices[":5005"].started)
+}
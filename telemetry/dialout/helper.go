@@ -0,0 +1,39 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package dialout
+
+import (
+	"net"
+
+	"github.com/yahoo/panoptes-stream/config"
+)
+
+// GetDevices returns the devices which have sensors with the given service
+// and the resolved peer addresses to the devices' host.
+func GetDevices(devices []config.Device, service string) (map[string]config.Device, map[string]string) {
+	var (
+		dialoutDevices = make(map[string]config.Device)
+		peers          = make(map[string]string)
+	)
+
+	for _, device := range devices {
+		if len(device.Sensors[service]) < 1 {
+			continue
+		}
+
+		dialoutDevices[device.Host] = device
+		peers[device.Host] = device.Host
+
+		addrs, err := net.LookupHost(device.Host)
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			peers[addr] = device.Host
+		}
+	}
+
+	return dialoutDevices, peers
+}
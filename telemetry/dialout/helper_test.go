@@ -0,0 +1,30 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package dialout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yahoo/panoptes-stream/config"
+)
+
+func TestGetDevices(t *testing.T) {
+	devices := []config.Device{
+		{
+			DeviceConfig: config.DeviceConfig{Host: "127.0.0.1"},
+			Sensors:      map[string][]*config.Sensor{"test.dialout": {{Service: "test.dialout"}}},
+		},
+		{
+			DeviceConfig: config.DeviceConfig{Host: "127.0.0.2"},
+			Sensors:      map[string][]*config.Sensor{"test.gnmi": {{Service: "test.gnmi"}}},
+		},
+	}
+
+	d, peers := GetDevices(devices, "test.dialout")
+	assert.Len(t, d, 1)
+	assert.Contains(t, d, "127.0.0.1")
+	assert.Equal(t, map[string]string{"127.0.0.1": "127.0.0.1"}, peers)
+}
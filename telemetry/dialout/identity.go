@@ -0,0 +1,113 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package dialout
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/secret"
+)
+
+// The dial-out services client auth modes, the client certificate
+// is verified if it's given (request) or it has to be given (require).
+const (
+	ClientAuthNone    = ""
+	ClientAuthRequest = "request"
+	ClientAuthRequire = "require"
+)
+
+var (
+	// ErrUnauthorizedPeer is returned once the client certificate is required but not available.
+	ErrUnauthorizedPeer = errors.New("client certificate not available")
+
+	// ErrUnknownPeer is returned once the peer doesn't belong to the configured devices.
+	ErrUnknownPeer = errors.New("device not found")
+)
+
+// GetTLSClientAuth returns the TLS client auth type for the client auth mode,
+// the certificate presence is checked by Identify to count the unauthorized peers.
+// the CA is the CA file or the remote certificate secret's CA (checked once it's loaded).
+func GetTLSClientAuth(clientAuth string, tlsConfig config.TLSConfig) (tls.ClientAuthType, error) {
+	switch clientAuth {
+	case ClientAuthNone:
+		return tls.NoClientCert, nil
+	case ClientAuthRequest, ClientAuthRequire:
+		if !tlsConfig.Enabled {
+			return tls.NoClientCert, fmt.Errorf("client auth %s requires TLS", clientAuth)
+		}
+
+		if _, _, ok := secret.ParseRemoteSecretInfo(tlsConfig.CertFile); !ok && tlsConfig.CAFile == "" {
+			return tls.NoClientCert, fmt.Errorf("client auth %s requires CA file", clientAuth)
+		}
+
+		return tls.VerifyClientCertIfGiven, nil
+	}
+
+	return tls.NoClientCert, fmt.Errorf("client auth %s not supported", clientAuth)
+}
+
+// GetIdentities returns the devices' identities (host and the configured identities) to the devices' host.
+func GetIdentities(devices map[string]config.Device) map[string]string {
+	identities := make(map[string]string)
+
+	for host, device := range devices {
+		identities[host] = host
+		for _, identity := range device.Identities {
+			identities[identity] = host
+		}
+	}
+
+	return identities
+}
+
+// GetCertNames returns the certificate common name, DNS and IP SANs.
+func GetCertNames(cert *x509.Certificate) []string {
+	var names []string
+
+	if cert.Subject.CommonName != "" {
+		names = append(names, cert.Subject.CommonName)
+	}
+
+	names = append(names, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+
+	return names
+}
+
+// Identify returns the device's host based on the verified client certificate names,
+// the peer address identifies the device once the certificate is not available and not required.
+// the unknown certificate falls back to the peer address only if the client auth is not enabled.
+func Identify(state *tls.ConnectionState, addr string, identities, peers map[string]string, clientAuth string) (string, error) {
+	if state != nil && len(state.PeerCertificates) > 0 {
+		for _, name := range GetCertNames(state.PeerCertificates[0]) {
+			if host, ok := identities[name]; ok {
+				return host, nil
+			}
+		}
+
+		if clientAuth != ClientAuthNone {
+			return "", ErrUnknownPeer
+		}
+	} else if clientAuth == ClientAuthRequire {
+		return "", ErrUnauthorizedPeer
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	if h, ok := peers[host]; ok {
+		return h, nil
+	}
+
+	return "", ErrUnknownPeer
+}
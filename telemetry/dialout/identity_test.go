@@ -0,0 +1,96 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package dialout
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yahoo/panoptes-stream/config"
+)
+
+func TestGetTLSClientAuth(t *testing.T) {
+	tlsConfig := config.TLSConfig{Enabled: true, CAFile: "/etc/panoptes/tls/ca.pem"}
+
+	clientAuth, err := GetTLSClientAuth("", config.TLSConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, tls.NoClientCert, clientAuth)
+
+	clientAuth, err = GetTLSClientAuth("require", tlsConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, clientAuth)
+
+	_, err = GetTLSClientAuth("require", config.TLSConfig{})
+	assert.Error(t, err)
+
+	_, err = GetTLSClientAuth("verify", tlsConfig)
+	assert.Error(t, err)
+
+	// CA not provided
+	_, err = GetTLSClientAuth("request", config.TLSConfig{Enabled: true})
+	assert.Error(t, err)
+
+	// the remote certificate secret holds the CA
+	clientAuth, err = GetTLSClientAuth("request", config.TLSConfig{Enabled: true, CertFile: "__vault::secrets/tls"})
+	assert.NoError(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, clientAuth)
+}
+
+func TestGetIdentities(t *testing.T) {
+	devices := map[string]config.Device{
+		"core1.lax": {DeviceConfig: config.DeviceConfig{Host: "core1.lax", Identities: []string{"core1-lax", "10.0.0.1"}}},
+		"core1.bur": {DeviceConfig: config.DeviceConfig{Host: "core1.bur"}},
+	}
+
+	assert.Equal(t, map[string]string{
+		"core1.lax": "core1.lax",
+		"core1-lax": "core1.lax",
+		"10.0.0.1":  "core1.lax",
+		"core1.bur": "core1.bur",
+	}, GetIdentities(devices))
+}
+
+func TestIdentify(t *testing.T) {
+	identities := map[string]string{"core1.lax": "core1.lax", "core1-lax": "core1.lax"}
+	peers := map[string]string{"127.0.0.1": "core1.lax"}
+
+	cert := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "panoptes"},
+		DNSNames:    []string{"core1-lax"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.2")},
+	}
+	assert.Equal(t, []string{"panoptes", "core1-lax", "127.0.0.2"}, GetCertNames(cert))
+
+	state := &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+
+	host, err := Identify(state, "127.0.0.5:50000", identities, peers, ClientAuthRequire)
+	assert.NoError(t, err)
+	assert.Equal(t, "core1.lax", host)
+
+	// the peer address without client certificate
+	host, err = Identify(nil, "127.0.0.1:50000", identities, peers, ClientAuthRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, "core1.lax", host)
+
+	_, err = Identify(nil, "127.0.0.1:50000", identities, peers, ClientAuthRequire)
+	assert.Equal(t, ErrUnauthorizedPeer, err)
+
+	_, err = Identify(nil, "127.0.0.2:50000", identities, peers, ClientAuthNone)
+	assert.Equal(t, ErrUnknownPeer, err)
+
+	// the unknown certificate
+	state.PeerCertificates[0] = &x509.Certificate{Subject: pkix.Name{CommonName: "edge1.lax"}}
+
+	_, err = Identify(state, "127.0.0.1:50000", identities, peers, ClientAuthRequest)
+	assert.Equal(t, ErrUnknownPeer, err)
+
+	host, err = Identify(state, "127.0.0.1:50000", identities, peers, ClientAuthNone)
+	assert.NoError(t, err)
+	assert.Equal(t, "core1.lax", host)
+}
@@ -0,0 +1,56 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package dialout
+
+import (
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Registrar represents dial-out service registry
+type Registrar struct {
+	s  map[string]Factory
+	lg *zap.Logger
+	sync.RWMutex
+}
+
+// NewRegistrar creates a new registrar instance
+func NewRegistrar(lg *zap.Logger) *Registrar {
+	return &Registrar{
+		s:  make(map[string]Factory),
+		lg: lg,
+	}
+}
+
+// Register adds new dial-out service factory
+func (dr *Registrar) Register(name, version string, df Factory) {
+	dr.lg.Info("dialout", zap.String("event", "register"), zap.String("name", name), zap.String("version", version))
+	dr.set(name, df)
+}
+
+// GetDialoutFactory returns requested dial-out service factory
+func (dr *Registrar) GetDialoutFactory(name string) (Factory, bool) {
+	// name convention: service[::name] example: cisco.mdt or cisco.mdt::edge
+	service := strings.Split(name, "::")
+	if len(service) < 1 {
+		return nil, false
+	}
+
+	return dr.get(service[0])
+}
+
+func (dr *Registrar) set(name string, df Factory) {
+	dr.Lock()
+	defer dr.Unlock()
+	dr.s[name] = df
+}
+
+func (dr *Registrar) get(name string) (Factory, bool) {
+	dr.RLock()
+	defer dr.RUnlock()
+	v, ok := dr.s[name]
+	return v, ok
+}
@@ -0,0 +1,27 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package dialout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yahoo/panoptes-stream/config"
+)
+
+func TestRegister(t *testing.T) {
+	logger := config.GetDefaultLogger()
+	registrar := NewRegistrar(logger)
+	registrar.Register("test", "0.0.0", newTestService)
+
+	_, ok := registrar.GetDialoutFactory("test")
+	assert.Equal(t, true, ok)
+
+	_, ok = registrar.GetDialoutFactory("test::edge")
+	assert.Equal(t, true, ok)
+
+	_, ok = registrar.GetDialoutFactory("unknown")
+	assert.Equal(t, false, ok)
+}
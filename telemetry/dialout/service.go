@@ -0,0 +1,21 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package dialout
+
+import (
+	"context"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+// Factory is a function that returns a new instance of a dial-out service
+type Factory func(context.Context, config.Config, config.DialoutService, telemetry.ExtDSChan) Service
+
+// Service represents a dial-out service
+type Service interface {
+	Start() error
+	Update()
+	Stop()
+}
@@ -0,0 +1,21 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package generic
+
+import (
+	"github.com/yahoo/panoptes-stream/telemetry"
+	"github.com/yahoo/panoptes-stream/telemetry/dialout"
+	"github.com/yahoo/panoptes-stream/telemetry/generic/gnmi"
+)
+
+// Register vendor-neutral telemetries
+func Register(telemetryRegistrar *telemetry.Registrar) {
+	telemetryRegistrar.Register("gnmi", gnmi.Version(), gnmi.New)
+	telemetryRegistrar.Register("gnmi.get", gnmi.Version(), gnmi.NewGet)
+}
+
+// RegisterDialout vendor-neutral dial-out telemetries
+func RegisterDialout(dialoutRegistrar *dialout.Registrar) {
+	dialoutRegistrar.Register("gnmi", gnmi.Version(), gnmi.NewDialout)
+}
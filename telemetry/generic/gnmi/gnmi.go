@@ -0,0 +1,324 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package gnmi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/status"
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+var gnmiVersion = "0.7.0"
+
+// GNMI represents a vendor-neutral gNMI.
+type GNMI struct {
+	conn          *grpc.ClientConn
+	subscriptions []*telemetry.GNMISubscriptionGroup
+
+	dataChan chan telemetry.GNMIResponse
+	outChan  telemetry.ExtDSChan
+	logger   *zap.Logger
+
+	metrics map[string]status.Metrics
+
+	pathOutput    map[string]string
+	defaultOutput string
+
+	// labels are the dial-out device's labels
+	labels map[string]string
+
+	opts Options
+}
+
+// Options represents the vendor specific behaviors on top of the vendor-neutral gNMI.
+type Options struct {
+	// Name is the telemetry name e.g. nokia.gnmi, the metrics are prefixed by
+	// the name and the workers are set by its env variable e.g. NOKIA_GNMI_WORKERS.
+	Name string
+	// Encoding is the requested encoding once the device supports it.
+	Encoding gpb.Encoding
+	// NormalizePath converts the vendor specific path elements e.g. the module names.
+	NormalizePath func([]*gpb.PathElem) []*gpb.PathElem
+	// GetValues returns the update values per key e.g. a JSON container per leaf.
+	GetValues func(key string, path []*gpb.PathElem, tv *gpb.TypedValue) (map[string]interface{}, error)
+}
+
+var defaultOptions = Options{
+	Name:     "gnmi",
+	Encoding: gpb.Encoding_PROTO,
+}
+
+// New creates a vendor-neutral gNMI and register proper metrics.
+func New(logger *zap.Logger, conn *grpc.ClientConn, sensors []*config.Sensor, outChan telemetry.ExtDSChan) telemetry.NMI {
+	return newGNMI(defaultOptions, logger, conn, sensors, outChan)
+}
+
+// NewVendor returns a gNMI factory with the vendor specific options.
+func NewVendor(opts Options) telemetry.NMIFactory {
+	return func(logger *zap.Logger, conn *grpc.ClientConn, sensors []*config.Sensor, outChan telemetry.ExtDSChan) telemetry.NMI {
+		return newGNMI(opts, logger, conn, sensors, outChan)
+	}
+}
+
+func newGNMI(opts Options, logger *zap.Logger, conn *grpc.ClientConn, sensors []*config.Sensor, outChan telemetry.ExtDSChan) *GNMI {
+	var (
+		metrics = make(map[string]status.Metrics)
+		prefix  = strings.Replace(opts.Name, ".", "_", -1)
+	)
+
+	metrics["gRPCDataTotal"] = status.NewCounter(prefix+"_grpc_data_total", "")
+	metrics["dropsTotal"] = status.NewCounter(prefix+"_drops_total", "")
+	metrics["errorsTotal"] = status.NewCounter(prefix+"_errors_total", "")
+	metrics["processNSecond"] = status.NewGauge(prefix+"_process_nanosecond", "")
+
+	status.Register(status.Labels{"host": conn.Target()}, metrics)
+
+	return &GNMI{
+		opts:          opts,
+		logger:        logger,
+		conn:          conn,
+		subscriptions: telemetry.GetGNMISubscriptionGroups(sensors),
+		pathOutput:    telemetry.GetPathOutput(sensors),
+		defaultOutput: telemetry.GetDefaultOutput(sensors),
+		dataChan:      make(chan telemetry.GNMIResponse, 100),
+		outChan:       outChan,
+		metrics:       metrics,
+	}
+}
+
+// Start starts to get stream and fan-out to workers.
+func (g *GNMI) Start(ctx context.Context) error {
+	defer status.Unregister(status.Labels{"host": g.conn.Target()}, g.metrics)
+
+	workers := config.GetEnvInt(strings.ToUpper(strings.Replace(g.opts.Name, ".", "_", -1))+"_WORKERS", 1)
+	for i := 0; i < workers; i++ {
+		go g.worker(ctx)
+	}
+
+	client := gpb.NewGNMIClient(g.conn)
+
+	encoding, subscriptions, err := telemetry.GNMICapabilitiesHandshake(ctx, client, g.conn.Target(), g.opts.Name, g.opts.Encoding, g.subscriptions, g.logger)
+	if err != nil {
+		return err
+	}
+
+	return telemetry.GNMISubscribe(ctx, client, encoding, subscriptions, g.dataChan, g.metrics["gRPCDataTotal"])
+}
+
+func (g *GNMI) worker(ctx context.Context) {
+	var (
+		start          time.Time
+		buf            = new(bytes.Buffer)
+		systemID, _, _ = net.SplitHostPort(g.conn.Target())
+	)
+
+	for {
+		select {
+		case d, ok := <-g.dataChan:
+			if !ok {
+				return
+			}
+
+			start = time.Now()
+
+			if d.GetSyncResponse() {
+				if err := telemetry.SyncResponse(g.outChan, g.pathOutput, d.Group.Paths, systemID); err != nil {
+					g.metrics["dropsTotal"].Inc()
+					g.logger.Error(g.opts.Name, zap.Error(err))
+				}
+				continue
+			}
+
+			resp, ok := d.Response.(*gpb.SubscribeResponse_Update)
+			if !ok {
+				continue
+			}
+
+			for _, update := range resp.Update.Update {
+				if err := g.datastore(buf, resp.Update, update, systemID); err != nil {
+					g.metrics["errorsTotal"].Inc()
+					g.logger.Error(g.opts.Name, zap.Error(err))
+				}
+			}
+
+			for _, path := range resp.Update.Delete {
+				if err := g.datastore(buf, resp.Update, &gpb.Update{Path: path}, systemID); err != nil {
+					g.metrics["errorsTotal"].Inc()
+					g.logger.Error(g.opts.Name, zap.Error(err))
+				}
+			}
+
+			g.metrics["processNSecond"].Set(uint64(time.Since(start).Nanoseconds()))
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (g *GNMI) datastore(buf *bytes.Buffer, n *gpb.Notification, update *gpb.Update, systemID string) error {
+	var path []*gpb.PathElem
+
+	if n.Prefix != nil {
+		path = append(path, n.Prefix.Elem...)
+	}
+
+	if update.Path != nil {
+		path = append(path, update.Path.Elem...)
+	}
+
+	if g.opts.NormalizePath != nil {
+		path = g.opts.NormalizePath(path)
+	}
+
+	prefix, prefixLabels, output, idx := g.getPrefix(buf, path)
+
+	if g.defaultOutput != "" {
+		output = g.defaultOutput
+	} else if output == "" {
+		return fmt.Errorf("output not found - %s", pathString(path))
+	}
+
+	buf.Reset()
+	key, keyLabels := telemetry.GetKey(buf, path[idx:])
+	labels := telemetry.MergeLabels(keyLabels, prefixLabels, prefix)
+
+	for k, v := range g.labels {
+		if _, ok := labels[k]; !ok {
+			labels[k] = v
+		}
+	}
+
+	values, err := g.getValues(key, path, update.Val)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range values {
+		ds := telemetry.DataStore{
+			"prefix":    prefix,
+			"labels":    labels,
+			"timestamp": n.Timestamp,
+			"system_id": systemID,
+			"key":       key,
+			"value":     value,
+		}
+
+		if update.Val == nil {
+			ds["operation"] = telemetry.OperationDelete
+		}
+
+		select {
+		case g.outChan <- telemetry.ExtDataStore{
+			DS:     ds,
+			Output: output,
+		}:
+		default:
+			g.metrics["dropsTotal"].Inc()
+			return errors.New("dataset drop")
+		}
+	}
+
+	return nil
+}
+
+// getValues returns the update values per key, the deletes are updates without value.
+func (g *GNMI) getValues(key string, path []*gpb.PathElem, tv *gpb.TypedValue) (map[string]interface{}, error) {
+	if tv == nil {
+		return map[string]interface{}{key: nil}, nil
+	}
+
+	if g.opts.GetValues != nil {
+		return g.opts.GetValues(key, path, tv)
+	}
+
+	value, err := telemetry.GetValue(tv)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{key: value}, nil
+}
+
+// getPrefix returns the longest configured sensor path that matches the
+// given path as prefix along with its labels, output and the index of
+// the first path element after the prefix. sensor paths are looked up
+// without keys first and then with keys in sorted order e.g. /a/b[k=v]/c
+func (g *GNMI) getPrefix(buf *bytes.Buffer, path []*gpb.PathElem) (string, map[string]string, string, int) {
+	var (
+		prefix string
+		output string
+		idx    int
+	)
+
+	for i := 0; i < 2 && output == ""; i++ {
+		buf.Reset()
+
+		for n, elem := range path {
+			if len(elem.Name) > 0 {
+				buf.WriteRune('/')
+				buf.WriteString(elem.Name)
+			}
+
+			if i == 1 {
+				for _, key := range sortedKeys(elem.Key) {
+					buf.WriteString(fmt.Sprintf("[%s=%s]", key, elem.Key[key]))
+				}
+			}
+
+			if o, ok := g.pathOutput[buf.String()+"/"]; ok {
+				output = o
+				idx = n + 1
+			}
+		}
+	}
+
+	buf.Reset()
+	key, labels := telemetry.GetKey(buf, path[:idx])
+	if idx > 0 {
+		prefix = "/" + key
+	}
+
+	return prefix, labels, output, idx
+}
+
+func pathString(path []*gpb.PathElem) string {
+	var s strings.Builder
+
+	for _, elem := range path {
+		s.WriteRune('/')
+		s.WriteString(elem.Name)
+	}
+
+	return s.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Version returns the current package version.
+func Version() string {
+	return gnmiVersion
+}
@@ -0,0 +1,347 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package gnmi
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/secret"
+	"github.com/yahoo/panoptes-stream/status"
+	"github.com/yahoo/panoptes-stream/telemetry"
+	"github.com/yahoo/panoptes-stream/telemetry/dialout"
+)
+
+// dialoutService is the sensor service name which binds
+// a device's sensors to the gNMI dial-out.
+const dialoutService = "gnmi.dialout"
+
+// handshakeTimeout bounds the TLS handshake of the devices connections.
+var handshakeTimeout = 10 * time.Second
+
+// Dialout represents gNMI dial-out. the devices initiate the TCP (or TLS)
+// connections and then Panoptes subscribes over the established
+// connections as gNMI client (reverse gNMI).
+type Dialout struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	cfg      config.Config
+	conf     config.DialoutService
+	outChan  telemetry.ExtDSChan
+	logger   *zap.Logger
+	metrics  map[string]status.Metrics
+	devices  map[string]config.Device
+	peers    map[string]string
+	sessions map[string]*session
+	closeTLS func()
+
+	identities map[string]string
+
+	sync.RWMutex
+}
+
+type session struct {
+	device config.Device
+	cancel context.CancelFunc
+}
+
+// NewDialout returns a new instance of gNMI dial-out.
+func NewDialout(ctx context.Context, cfg config.Config, conf config.DialoutService, outChan telemetry.ExtDSChan) dialout.Service {
+	var metrics = make(map[string]status.Metrics)
+
+	metrics["sessionsCurrent"] = status.NewGauge("gnmi_dialout_sessions", "")
+	metrics["unknownPeersTotal"] = status.NewCounter("gnmi_dialout_unknown_peers_total", "")
+	metrics["unauthorizedPeersTotal"] = status.NewCounter("gnmi_dialout_unauthorized_peers_total", "")
+	metrics["errorsTotal"] = status.NewCounter("gnmi_dialout_errors_total", "")
+
+	status.Register(status.Labels{"service": conf.Name}, metrics)
+
+	d := &Dialout{
+		cfg:      cfg,
+		conf:     conf,
+		outChan:  outChan,
+		logger:   cfg.Logger(),
+		metrics:  metrics,
+		sessions: make(map[string]*session),
+	}
+
+	d.devices, d.peers = dialout.GetDevices(cfg.Devices(), dialoutService)
+	d.identities = dialout.GetIdentities(d.devices)
+	d.ctx, d.cancel = context.WithCancel(ctx)
+
+	return d
+}
+
+// Start starts to accept the devices connections.
+func (d *Dialout) Start() error {
+	var (
+		tlsConfig *tls.Config
+		conf      = d.conf
+	)
+
+	if conf.Addr == "" {
+		return errors.New("address is empty")
+	}
+
+	clientAuth, err := dialout.GetTLSClientAuth(conf.ClientAuth, conf.TLSConfig)
+	if err != nil {
+		return err
+	}
+
+	if conf.TLSConfig.Enabled {
+		tlsConfig, d.closeTLS, err = secret.GetTLSServerReloadConfig(d.ctx, conf.Name, &conf.TLSConfig, clientAuth)
+		if err != nil {
+			return err
+		}
+	}
+
+	ln, err := net.Listen("tcp", conf.Addr)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-d.ctx.Done()
+		ln.Close()
+	}()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				if d.ctx.Err() == nil {
+					d.logger.Error("gnmi.dialout", zap.Error(err))
+				}
+				return
+			}
+
+			if tlsConfig != nil {
+				conn = tls.Server(conn, tlsConfig)
+			}
+
+			go d.session(conn)
+		}
+	}()
+
+	d.logger.Info("gnmi.dialout", zap.String("address", conf.Addr), zap.Bool("tls", conf.TLSConfig.Enabled))
+
+	return nil
+}
+
+// Stop stops the listener and terminates all sessions.
+func (d *Dialout) Stop() {
+	d.cancel()
+
+	if d.closeTLS != nil {
+		d.closeTLS()
+	}
+
+	status.Unregister(status.Labels{"service": d.conf.Name}, d.metrics)
+}
+
+// Update updates the devices once the configuration changed
+// the sessions of the deleted or modified devices are terminated.
+func (d *Dialout) Update() {
+	d.Lock()
+	defer d.Unlock()
+
+	d.devices, d.peers = dialout.GetDevices(d.cfg.Devices(), dialoutService)
+	d.identities = dialout.GetIdentities(d.devices)
+
+	for host, s := range d.sessions {
+		device, ok := d.devices[host]
+		if !ok || !reflect.DeepEqual(device, s.device) {
+			s.cancel()
+		}
+	}
+}
+
+func (d *Dialout) session(conn net.Conn) {
+	defer conn.Close()
+
+	device, err := d.identify(conn)
+	if err != nil {
+		if errors.Is(err, dialout.ErrUnknownPeer) {
+			d.metrics["unknownPeersTotal"].Inc()
+		} else {
+			d.metrics["unauthorizedPeersTotal"].Inc()
+		}
+		d.logger.Warn("gnmi.dialout", zap.String("event", "reject"), zap.String("peer", conn.RemoteAddr().String()), zap.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(d.ctx)
+	defer cancel()
+
+	s := d.register(device, cancel)
+	defer d.unregister(device.Host, s)
+
+	d.logger.Info("gnmi.dialout", zap.String("event", "connect"), zap.String("host", device.Host), zap.String("peer", conn.RemoteAddr().String()))
+
+	ctx, err = d.setCredentials(ctx, &device)
+	if err != nil {
+		d.metrics["errorsTotal"].Inc()
+		d.logger.Error("gnmi.dialout", zap.String("event", "credentials"), zap.Error(err))
+		return
+	}
+
+	var once sync.Once
+	dialer := func(context.Context, string) (net.Conn, error) {
+		var c net.Conn
+		once.Do(func() { c = conn })
+		if c == nil {
+			return nil, errors.New("dial-out connection has been closed")
+		}
+		return c, nil
+	}
+
+	gCtx, gCancel := context.WithTimeout(ctx, d.getTimeout(device.Timeout))
+	target := net.JoinHostPort(device.Host, strconv.Itoa(device.Port))
+	opts := []grpc.DialOption{
+		grpc.WithContextDialer(dialer),
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.WithUserAgent("Panoptes"),
+	}
+	transportOpts := telemetry.GetTransportOptions(device.DeviceOptions, d.cfg.Global().DeviceOptions)
+	opts = append(opts, telemetry.GetTransportDialOpts(transportOpts)...)
+
+	gConn, err := grpc.DialContext(gCtx, target, opts...)
+	gCancel()
+	if err != nil {
+		d.metrics["errorsTotal"].Inc()
+		d.logger.Error("gnmi.dialout", zap.String("event", "grpc.dial"), zap.String("host", device.Host), zap.Error(err))
+		return
+	}
+	defer gConn.Close()
+
+	nmi := New(d.logger, gConn, device.Sensors[dialoutService], d.outChan).(*GNMI)
+	nmi.labels = device.Labels
+
+	if err := nmi.Start(ctx); err != nil {
+		d.logger.Warn("gnmi.dialout", zap.String("event", "nmi"), zap.String("host", device.Host), zap.Error(err))
+	} else {
+		d.logger.Warn("gnmi.dialout", zap.String("event", "terminate"), zap.String("host", device.Host))
+	}
+}
+
+// identify returns the configured device based on the TLS
+// client certificate identity (if available) or the peer address.
+func (d *Dialout) identify(conn net.Conn) (config.Device, error) {
+	var state *tls.ConnectionState
+
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn.SetDeadline(time.Now().Add(handshakeTimeout))
+		if err := tlsConn.Handshake(); err != nil {
+			return config.Device{}, err
+		}
+		conn.SetDeadline(time.Time{})
+
+		s := tlsConn.ConnectionState()
+		state = &s
+	}
+
+	d.RLock()
+	defer d.RUnlock()
+
+	host, err := dialout.Identify(state, conn.RemoteAddr().String(), d.identities, d.peers, d.conf.ClientAuth)
+	if err != nil {
+		return config.Device{}, err
+	}
+
+	if device, ok := d.devices[host]; ok {
+		return device, nil
+	}
+
+	return config.Device{}, dialout.ErrUnknownPeer
+}
+
+// register registers the device session, the current session of the device is
+// terminated and replaced as the device reconnects once its connection dropped
+// e.g. half-open connection. the returned session identifies it to unregister.
+func (d *Dialout) register(device config.Device, cancel context.CancelFunc) *session {
+	d.Lock()
+	defer d.Unlock()
+
+	if s, ok := d.sessions[device.Host]; ok {
+		d.logger.Warn("gnmi.dialout", zap.String("event", "replace"), zap.String("host", device.Host))
+		s.cancel()
+	} else {
+		d.metrics["sessionsCurrent"].Inc()
+	}
+
+	s := &session{device: device, cancel: cancel}
+	d.sessions[device.Host] = s
+
+	return s
+}
+
+// unregister removes the session unless it has been replaced.
+func (d *Dialout) unregister(host string, s *session) {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.sessions[host] != s {
+		return
+	}
+
+	delete(d.sessions, host)
+	d.metrics["sessionsCurrent"].Dec()
+}
+
+func (d *Dialout) setCredentials(ctx context.Context, device *config.Device) (context.Context, error) {
+	var (
+		dOptions           = d.cfg.Global().DeviceOptions
+		username, password string
+	)
+
+	if device.Username != "" {
+		username, password = device.Username, device.Password
+	} else {
+		username, password = dOptions.Username, dOptions.Password
+	}
+
+	if username == "" {
+		return ctx, nil
+	}
+
+	sType, path, ok := secret.ParseRemoteSecretInfo(username)
+	if ok {
+		secrets, err := secret.GetCredentials(sType, path)
+		if err != nil {
+			return ctx, err
+		}
+
+		for u, p := range secrets {
+			return metadata.AppendToOutgoingContext(ctx, "username", u, "password", p), nil
+		}
+
+		return ctx, errors.New("credentials are not available at remote host")
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, "username", username, "password", password), nil
+}
+
+func (d *Dialout) getTimeout(timeout int) time.Duration {
+	gTimeout := d.cfg.Global().DeviceOptions.Timeout
+
+	if timeout != 0 {
+		return time.Second * time.Duration(timeout)
+	} else if gTimeout != 0 {
+		return time.Second * time.Duration(gTimeout)
+	}
+
+	return time.Second * 5
+}
@@ -0,0 +1,272 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package gnmi
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/telemetry"
+	"github.com/yahoo/panoptes-stream/telemetry/dialout"
+	"github.com/yahoo/panoptes-stream/telemetry/mock"
+)
+
+// holdUpdate sends the updates and keeps the stream open.
+type holdUpdate struct {
+	mock.Update
+}
+
+func (h holdUpdate) Run(server gpb.GNMI_SubscribeServer) error {
+	if err := h.Update.Run(server); err != nil {
+		return err
+	}
+
+	<-server.Context().Done()
+
+	return nil
+}
+
+func TestDialoutStart(t *testing.T) {
+	var (
+		addr = "127.0.0.1:50511"
+		cfg  = config.NewMockConfig()
+		ch   = make(telemetry.ExtDSChan, 1)
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg.Global().Dialout = config.Dialout{
+		Services: map[string]config.DialoutService{
+			"gnmi": {Addr: addr},
+		},
+	}
+
+	cfg.MDevices = []config.Device{
+		{
+			DeviceConfig: config.DeviceConfig{Host: "127.0.0.1"},
+			Sensors: map[string][]*config.Sensor{
+				"gnmi.dialout": {
+					{
+						Service: "gnmi.dialout",
+						Output:  "console::stdout",
+						Path:    "/interfaces/interface/state/counters",
+					},
+				},
+			},
+		},
+	}
+
+	d := NewDialout(ctx, cfg, cfg.Global().Dialout.Services["gnmi"], ch).(*Dialout)
+	err := d.Start()
+	assert.NoError(t, err)
+
+	ln, err := mock.StartGNMIDialout(addr, holdUpdate{mock.Update{Notification: mock.AristaUpdate(), Attempt: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	select {
+	case resp := <-ch:
+		assert.Equal(t, "/interfaces/interface/state/counters", resp.DS["prefix"])
+		assert.Equal(t, "127.0.0.1", resp.DS["system_id"])
+		assert.Equal(t, "out-octets", resp.DS["key"])
+		assert.Equal(t, int64(50302030597), resp.DS["value"])
+		assert.Equal(t, "console::stdout", resp.Output)
+	case <-time.After(time.Second * 3):
+		t.Fatal("timeout")
+	}
+}
+
+func TestDialoutUnknownPeer(t *testing.T) {
+	var (
+		addr = "127.0.0.1:50512"
+		cfg  = config.NewMockConfig()
+		ch   = make(telemetry.ExtDSChan, 1)
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg.Global().Dialout = config.Dialout{
+		Services: map[string]config.DialoutService{
+			"gnmi": {Addr: addr},
+		},
+	}
+
+	d := NewDialout(ctx, cfg, cfg.Global().Dialout.Services["gnmi"], ch).(*Dialout)
+	err := d.Start()
+	assert.NoError(t, err)
+
+	ln, err := mock.StartGNMIDialout(addr, mock.Update{Notification: mock.AristaUpdate(), Attempt: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	time.Sleep(time.Millisecond * 500)
+
+	assert.Equal(t, uint64(1), d.metrics["unknownPeersTotal"].Get())
+	assert.Len(t, ch, 0)
+}
+
+func TestDialoutUpdate(t *testing.T) {
+	var (
+		cfg       = config.NewMockConfig()
+		cancelled bool
+	)
+
+	device := config.Device{
+		DeviceConfig: config.DeviceConfig{Host: "127.0.0.1"},
+		Sensors: map[string][]*config.Sensor{
+			"gnmi.dialout": {{Service: "gnmi.dialout", Path: "/interfaces"}},
+		},
+	}
+
+	cfg.MDevices = []config.Device{device}
+
+	d := NewDialout(context.Background(), cfg, config.DialoutService{}, nil).(*Dialout)
+	d.sessions["127.0.0.1"] = &session{device: device, cancel: func() { cancelled = true }}
+
+	d.Update()
+	assert.False(t, cancelled)
+	assert.Contains(t, d.devices, "127.0.0.1")
+	assert.Equal(t, "127.0.0.1", d.peers["127.0.0.1"])
+
+	cfg.MDevices = nil
+	d.Update()
+	assert.True(t, cancelled)
+	assert.Len(t, d.devices, 0)
+}
+
+func TestDialoutClientAuth(t *testing.T) {
+	cfg := config.NewMockConfig()
+
+	// the client auth requires TLS
+	d := NewDialout(context.Background(), cfg, config.DialoutService{Addr: "127.0.0.1:50067", ClientAuth: "require"}, nil).(*Dialout)
+	assert.Error(t, d.Start())
+
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	_, err := d.identify(c1)
+	assert.Equal(t, dialout.ErrUnauthorizedPeer, err)
+
+	// the client which never sends its certificate is rejected as unauthorized
+	handshakeTimeout = 100 * time.Millisecond
+	defer func() { handshakeTimeout = 10 * time.Second }()
+
+	c3, c4 := net.Pipe()
+	defer c4.Close()
+
+	done := make(chan struct{})
+	go func() {
+		d.session(tls.Server(c3, &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert}))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		assert.Equal(t, uint64(1), d.metrics["unauthorizedPeersTotal"].Get())
+	case <-time.After(time.Second * 3):
+		t.Fatal("handshake is not bounded")
+	}
+}
+
+func TestDialoutReconnect(t *testing.T) {
+	var (
+		addr = "127.0.0.1:50513"
+		cfg  = config.NewMockConfig()
+		ch   = make(telemetry.ExtDSChan, 10)
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg.MDevices = []config.Device{
+		{
+			DeviceConfig: config.DeviceConfig{Host: "127.0.0.1"},
+			Sensors: map[string][]*config.Sensor{
+				"gnmi.dialout": {{Service: "gnmi.dialout", Output: "console::stdout", Path: "/interfaces/interface/state/counters"}},
+			},
+		},
+	}
+
+	d := NewDialout(ctx, cfg, config.DialoutService{Addr: addr}, ch).(*Dialout)
+	err := d.Start()
+	assert.NoError(t, err)
+
+	// the first connection stays open (half-open)
+	ln1, err := mock.StartGNMIDialout(addr, holdUpdate{mock.Update{Notification: mock.AristaUpdate(), Attempt: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln1.Close()
+
+	select {
+	case <-ch:
+	case <-time.After(time.Second * 3):
+		t.Fatal("timeout")
+	}
+
+	d.RLock()
+	first := d.sessions["127.0.0.1"]
+	d.RUnlock()
+
+	// the device reconnects and replaces the session
+	ln2, err := mock.StartGNMIDialout(addr, holdUpdate{mock.Update{Notification: mock.AristaUpdate(), Attempt: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln2.Close()
+
+	select {
+	case <-ch:
+	case <-time.After(time.Second * 3):
+		t.Fatal("timeout")
+	}
+
+	time.Sleep(time.Millisecond * 200)
+
+	d.RLock()
+	defer d.RUnlock()
+
+	assert.Len(t, d.sessions, 1)
+	assert.NotEqual(t, first, d.sessions["127.0.0.1"])
+	assert.Equal(t, uint64(1), d.metrics["sessionsCurrent"].Get())
+}
+
+func TestDialoutHandshakeTimeout(t *testing.T) {
+	handshakeTimeout = 100 * time.Millisecond
+	defer func() { handshakeTimeout = 10 * time.Second }()
+
+	d := NewDialout(context.Background(), config.NewMockConfig(), config.DialoutService{}, nil).(*Dialout)
+
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	// the peer connects and never sends the client hello
+	done := make(chan error)
+	go func() {
+		_, err := d.identify(tls.Server(c1, &tls.Config{}))
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(time.Second * 3):
+		t.Fatal("handshake is not bounded")
+	}
+}
@@ -0,0 +1,308 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package gnmi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/ygot/ygot"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/status"
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+const (
+	getService            = "gnmi.get"
+	defaultSampleInterval = 60
+)
+
+// Get represents a periodic gNMI Get poller for the paths
+// which they're not supported by subscribe.
+type Get struct {
+	conn    *grpc.ClientConn
+	sensors []*config.Sensor
+
+	outChan telemetry.ExtDSChan
+	logger  *zap.Logger
+
+	metrics map[string]status.Metrics
+}
+
+// NewGet creates a gNMI Get poller and register proper metrics.
+func NewGet(logger *zap.Logger, conn *grpc.ClientConn, sensors []*config.Sensor, outChan telemetry.ExtDSChan) telemetry.NMI {
+	var metrics = make(map[string]status.Metrics)
+
+	metrics["gRPCDataTotal"] = status.NewCounter("gnmi_get_grpc_data_total", "")
+	metrics["dropsTotal"] = status.NewCounter("gnmi_get_drops_total", "")
+	metrics["errorsTotal"] = status.NewCounter("gnmi_get_errors_total", "")
+	metrics["processNSecond"] = status.NewGauge("gnmi_get_process_nanosecond", "")
+
+	status.Register(status.Labels{"host": conn.Target()}, metrics)
+
+	return &Get{
+		logger:  logger,
+		conn:    conn,
+		sensors: sensors,
+		outChan: outChan,
+		metrics: metrics,
+	}
+}
+
+// Start polls the sensors per their sample interval till the context canceled,
+// a failed get request doesn't terminate the poller.
+func (g *Get) Start(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	defer status.Unregister(status.Labels{"host": g.conn.Target()}, g.metrics)
+
+	client := gpb.NewGNMIClient(g.conn)
+
+	for _, sensor := range g.sensors {
+		req, err := getRequest(sensor)
+		if err != nil {
+			return err
+		}
+
+		wg.Add(1)
+		go func(sensor *config.Sensor) {
+			defer wg.Done()
+			g.poll(ctx, client, req, sensor)
+		}(sensor)
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+func (g *Get) poll(ctx context.Context, client gpb.GNMIClient, req *gpb.GetRequest, sensor *config.Sensor) {
+	var (
+		buf            = new(bytes.Buffer)
+		systemID, _, _ = net.SplitHostPort(g.conn.Target())
+	)
+
+	interval := time.Duration(sensor.SampleInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultSampleInterval * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+
+		resp, err := client.Get(ctx, req)
+		if err != nil && ctx.Err() == nil {
+			g.metrics["errorsTotal"].Inc()
+			g.logger.Error(getService, zap.String("path", sensor.Path), zap.Error(err))
+		}
+
+		if err == nil {
+			g.metrics["gRPCDataTotal"].Inc()
+
+			for _, n := range resp.Notification {
+				for _, update := range n.Update {
+					if err := g.datastore(buf, n, update, req.Path[0], sensor.Output, systemID); err != nil {
+						g.metrics["errorsTotal"].Inc()
+						g.logger.Error(getService, zap.Error(err))
+					}
+				}
+			}
+
+			g.metrics["processNSecond"].Set(uint64(time.Since(start).Nanoseconds()))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// datastore flattens the update to the leaves, the elements of the requested
+// path are the prefix and the rest of the path and the JSON members are the key.
+func (g *Get) datastore(buf *bytes.Buffer, n *gpb.Notification, update *gpb.Update, reqPath *gpb.Path, output, systemID string) error {
+	var path []*gpb.PathElem
+
+	if n.Prefix != nil {
+		path = append(path, n.Prefix.Elem...)
+	}
+
+	if update.Path != nil {
+		path = append(path, update.Path.Elem...)
+	}
+
+	value, err := GetJSONValue(update.Val, leafName(path))
+	if err != nil {
+		return err
+	}
+
+	idx := len(reqPath.Elem)
+	if idx > len(path) {
+		idx = len(path)
+	}
+
+	// the leaf name is the key once the requested path is a leaf
+	if _, ok := value.(map[string]interface{}); !ok && idx == len(path) && idx > 0 {
+		idx--
+	}
+
+	buf.Reset()
+	prefix, prefixLabels := telemetry.GetKey(buf, path[:idx])
+	if prefix != "" {
+		prefix = "/" + prefix
+	}
+
+	buf.Reset()
+	key, keyLabels := telemetry.GetKey(buf, path[idx:])
+	labels := telemetry.MergeLabels(keyLabels, prefixLabels, prefix)
+
+	// the value of a list or container may be wrapped by its name
+	if m, ok := value.(map[string]interface{}); ok && len(m) == 1 && key == "" && idx > 0 {
+		for k, v := range m {
+			if trimModule(k) == trimModule(path[idx-1].Name) {
+				value = v
+			}
+		}
+	}
+
+	var dropped bool
+
+	walk(key, labels, value, func(key string, labels map[string]string, value interface{}) {
+		ds := telemetry.DataStore{
+			"prefix":    prefix,
+			"labels":    labels,
+			"timestamp": n.Timestamp,
+			"system_id": systemID,
+			"key":       key,
+			"value":     value,
+		}
+
+		select {
+		case g.outChan <- telemetry.ExtDataStore{
+			DS:     ds,
+			Output: output,
+		}:
+		default:
+			g.metrics["dropsTotal"].Inc()
+			dropped = true
+		}
+	})
+
+	if dropped {
+		return errors.New("dataset drop")
+	}
+
+	return nil
+}
+
+// walk flattens the JSON value to the leaves. the scalar members of a list
+// entry are the list keys (OpenConfig convention) and they're added as labels.
+func walk(key string, labels map[string]string, value interface{}, emit func(string, map[string]string, interface{})) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			walk(joinKey(key, trimModule(k)), labels, val, emit)
+		}
+	case []interface{}:
+		if !isEntryList(v) {
+			emit(key, labels, v)
+			return
+		}
+
+		for _, entry := range v {
+			entryLabels := make(map[string]string, len(labels))
+			for k, val := range labels {
+				entryLabels[k] = val
+			}
+
+			m := entry.(map[string]interface{})
+			for k, val := range m {
+				switch val.(type) {
+				case map[string]interface{}, []interface{}:
+				default:
+					name := trimModule(k)
+					if _, ok := entryLabels[name]; ok {
+						name = joinKey(key, name)
+					}
+					entryLabels[name] = fmt.Sprint(val)
+				}
+			}
+
+			for k, val := range m {
+				switch val.(type) {
+				case map[string]interface{}, []interface{}:
+					walk(joinKey(key, trimModule(k)), entryLabels, val, emit)
+				}
+			}
+		}
+	case nil:
+	default:
+		emit(key, labels, v)
+	}
+}
+
+func isEntryList(list []interface{}) bool {
+	for _, v := range list {
+		if _, ok := v.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+
+	return len(list) > 0
+}
+
+func joinKey(key, name string) string {
+	if key == "" {
+		return name
+	}
+
+	return key + "/" + name
+}
+
+func trimModule(name string) string {
+	if i := strings.Index(name, ":"); i > -1 {
+		return name[i+1:]
+	}
+
+	return name
+}
+
+// getRequest returns the get request of the sensor, the encoding
+// is JSON_IETF unless the sensor encoding is configured.
+func getRequest(sensor *config.Sensor) (*gpb.GetRequest, error) {
+	path, err := ygot.StringToPath(sensor.Path, ygot.StructuredPath, ygot.StringSlicePath)
+	if err != nil {
+		return nil, err
+	}
+	path.Origin = sensor.Origin
+
+	encoding := gpb.Encoding_JSON_IETF
+	if sensor.Encoding != "" {
+		e, ok := gpb.Encoding_value[strings.ToUpper(sensor.Encoding)]
+		if !ok {
+			return nil, fmt.Errorf("invalid encoding %s", sensor.Encoding)
+		}
+		encoding = gpb.Encoding(e)
+	}
+
+	return &gpb.GetRequest{
+		Path:     []*gpb.Path{path},
+		Type:     gpb.GetRequest_ALL,
+		Encoding: encoding,
+	}, nil
+}
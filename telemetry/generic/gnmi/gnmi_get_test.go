@@ -0,0 +1,112 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package gnmi
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/telemetry"
+	"github.com/yahoo/panoptes-stream/telemetry/mock"
+)
+
+func TestGetStart(t *testing.T) {
+	var (
+		addr    = "127.0.0.1:50058"
+		ch      = make(telemetry.ExtDSChan, 10)
+		sensors []*config.Sensor
+	)
+
+	ln, err := mock.StartGNMIGetServer(addr, mock.GetInterfaces())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	cfg := config.NewMockConfig()
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	sensors = append(sensors, &config.Sensor{
+		Service:        "gnmi.get",
+		Output:         "console::stdout",
+		Path:           "/interfaces/interface",
+		SampleInterval: 10,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	g := NewGet(cfg.Logger(), conn, sensors, ch)
+	go g.Start(ctx)
+
+	r := make(map[string]telemetry.ExtDataStore)
+	for i := 0; i < 5; i++ {
+		select {
+		case resp := <-ch:
+			r[resp.DS["key"].(string)] = resp
+		case <-ctx.Done():
+			t.Fatal("timeout")
+		}
+	}
+
+	resp := r["state/counters/in-octets"]
+	assert.Equal(t, "/interfaces/interface", resp.DS["prefix"])
+	assert.Equal(t, map[string]string{"name": "Ethernet1"}, resp.DS["labels"])
+	assert.Equal(t, uint64(50302030597), resp.DS["value"])
+	assert.Equal(t, int64(1595363593437180059), resp.DS["timestamp"])
+	assert.Equal(t, "127.0.0.1", resp.DS["system_id"])
+	assert.Equal(t, "console::stdout", resp.Output)
+
+	assert.Equal(t, uint64(9214), r["config/mtu"].DS["value"])
+	assert.Equal(t, "UP", r["state/oper-status"].DS["value"])
+	assert.Contains(t, r, "config/name")
+	assert.Contains(t, r, "state/counters/out-octets")
+}
+
+func TestGetDatastoreLeaf(t *testing.T) {
+	var (
+		cfg = config.NewMockConfig()
+		ch  = make(telemetry.ExtDSChan, 10)
+		buf = new(bytes.Buffer)
+	)
+
+	g := Get{logger: cfg.Logger(), outChan: ch}
+
+	req, err := getRequest(&config.Sensor{Path: "/system/state/hostname", Encoding: "json"})
+	assert.NoError(t, err)
+	assert.Equal(t, gpb.Encoding_JSON, req.Encoding)
+
+	n := &gpb.Notification{
+		Timestamp: 1595363593437180059,
+		Update: []*gpb.Update{
+			{
+				Path: req.Path[0],
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_JsonVal{JsonVal: []byte(`"core1.lax"`)}},
+			},
+		},
+	}
+
+	err = g.datastore(buf, n, n.Update[0], req.Path[0], "console::stdout", "127.0.0.1")
+	assert.NoError(t, err)
+
+	resp := <-ch
+	assert.Equal(t, "/system/state", resp.DS["prefix"])
+	assert.Equal(t, "hostname", resp.DS["key"])
+	assert.Equal(t, "core1.lax", resp.DS["value"])
+
+	_, err = getRequest(&config.Sensor{Path: "/system/state/hostname", Encoding: "xml"})
+	assert.Error(t, err)
+}
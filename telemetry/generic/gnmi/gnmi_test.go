@@ -0,0 +1,225 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package gnmi
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/status"
+	"github.com/yahoo/panoptes-stream/telemetry"
+	"github.com/yahoo/panoptes-stream/telemetry/mock"
+)
+
+func TestGNMISimplePath(t *testing.T) {
+	var (
+		addr    = "127.0.0.1:50510"
+		ch      = make(telemetry.ExtDSChan, 1)
+		ctx     = context.Background()
+		sensors []*config.Sensor
+	)
+	ln, err := mock.StartGNMIServer(addr, mock.Update{Notification: mock.AristaUpdate(), Attempt: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	cfg := config.NewMockConfig()
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sensors = append(sensors, &config.Sensor{
+		Service: "gnmi",
+		Output:  "console::stdout",
+		Path:    "/interfaces/interface/state/counters",
+	})
+
+	g := New(cfg.Logger(), conn, sensors, ch)
+	g.Start(ctx)
+
+	resp := <-ch
+
+	assert.Equal(t, sensors[0].Path, resp.DS["prefix"].(string))
+	assert.Equal(t, "127.0.0.1", resp.DS["system_id"].(string))
+	assert.Equal(t, int64(1595363593437180059), resp.DS["timestamp"].(int64))
+	assert.Equal(t, "Ethernet1", resp.DS["labels"].(map[string]string)["name"])
+	assert.Equal(t, "out-octets", resp.DS["key"].(string))
+	assert.Equal(t, int64(50302030597), resp.DS["value"].(int64))
+	assert.Equal(t, "console::stdout", resp.Output)
+
+	assert.Equal(t, "", cfg.LogOutput.String())
+}
+
+func TestDatastoreWithPrefix(t *testing.T) {
+	var (
+		cfg = config.NewMockConfig()
+		ch  = make(telemetry.ExtDSChan, 20)
+		buf = new(bytes.Buffer)
+		n   = mock.CiscoXRInterface()
+	)
+
+	g := GNMI{
+		logger:     cfg.Logger(),
+		pathOutput: map[string]string{"/interfaces/interface/": "out::out"},
+		outChan:    ch,
+	}
+
+	for _, update := range n.Update {
+		err := g.datastore(buf, n, update, "127.0.0.1")
+		assert.NoError(t, err)
+	}
+
+	assert.Len(t, ch, len(n.Update))
+
+	resp := <-ch
+	assert.Equal(t, "/interfaces/interface", resp.DS["prefix"])
+	assert.Equal(t, "state/counters/in-octets", resp.DS["key"])
+	assert.Equal(t, map[string]string{"name": "GigabitEthernet0/0/0/0"}, resp.DS["labels"])
+	assert.Equal(t, uint64(102387), resp.DS["value"])
+	assert.Equal(t, "out::out", resp.Output)
+}
+
+func TestDatastoreKeyPath(t *testing.T) {
+	var (
+		cfg = config.NewMockConfig()
+		ch  = make(telemetry.ExtDSChan, 20)
+		buf = new(bytes.Buffer)
+		n   = mock.AristaBGPUpdate()
+	)
+
+	g := GNMI{
+		logger:     cfg.Logger(),
+		pathOutput: map[string]string{"/network-instances/network-instance/": "out::default"},
+		outChan:    ch,
+	}
+
+	err := g.datastore(buf, n, n.Update[0], "127.0.0.1")
+	assert.NoError(t, err)
+
+	resp := <-ch
+	assert.Equal(t, "out::default", resp.Output)
+	assert.Equal(t, "/network-instances/network-instance", resp.DS["prefix"])
+	assert.Equal(t, "protocols/protocol/bgp/global/afi-safis/afi-safi/config/afi-safi-name", resp.DS["key"])
+	// the key label takes precedence and the prefix label is kept under its full path
+	assert.Equal(t, "BGP", resp.DS["labels"].(map[string]string)["name"])
+	assert.Equal(t, "default", resp.DS["labels"].(map[string]string)["/network-instances/network-instance/name"])
+	assert.Equal(t, "BGP", resp.DS["labels"].(map[string]string)["identifier"])
+
+	g.pathOutput = map[string]string{
+		"/network-instances/network-instance[name=default]/protocols/protocol[identifier=BGP][name=BGP]/": "out::bgp",
+	}
+
+	err = g.datastore(buf, n, n.Update[0], "127.0.0.1")
+	assert.NoError(t, err)
+
+	resp = <-ch
+	assert.Equal(t, "out::bgp", resp.Output)
+	assert.Equal(t, "/network-instances/network-instance/protocols/protocol", resp.DS["prefix"])
+	assert.Equal(t, "bgp/global/afi-safis/afi-safi/config/afi-safi-name", resp.DS["key"])
+	assert.Equal(t, "IPV6_UNICAST", resp.DS["labels"].(map[string]string)["afi-safi-name"])
+}
+
+func TestDatastoreOutputNotFound(t *testing.T) {
+	var (
+		cfg     = config.NewMockConfig()
+		buf     = new(bytes.Buffer)
+		n       = mock.AristaUpdate()
+		metrics = make(map[string]status.Metrics)
+	)
+
+	metrics["dropsTotal"] = status.NewCounter("gnmi_drops_total", "")
+
+	g := GNMI{
+		logger:     cfg.Logger(),
+		pathOutput: map[string]string{"/components/component/": "out::out"},
+		outChan:    make(telemetry.ExtDSChan, 1),
+		metrics:    metrics,
+	}
+
+	err := g.datastore(buf, n, n.Update[0], "127.0.0.1")
+	assert.Error(t, err)
+}
+
+func TestVersion(t *testing.T) {
+	assert.Equal(t, gnmiVersion, Version())
+}
+
+func TestDatastoreDelete(t *testing.T) {
+	var (
+		cfg = config.NewMockConfig()
+		ch  = make(telemetry.ExtDSChan, 20)
+		buf = new(bytes.Buffer)
+		n   = mock.CiscoXRInterface()
+	)
+
+	g := GNMI{
+		logger:     cfg.Logger(),
+		pathOutput: map[string]string{"/interfaces/interface/": "out::out"},
+		outChan:    ch,
+	}
+
+	err := g.datastore(buf, n, &gpb.Update{Path: n.Update[0].Path}, "127.0.0.1")
+	assert.NoError(t, err)
+
+	resp := <-ch
+	assert.Equal(t, "/interfaces/interface", resp.DS["prefix"])
+	assert.Equal(t, "state/counters/in-octets", resp.DS["key"])
+	assert.Equal(t, telemetry.OperationDelete, resp.DS["operation"])
+	assert.Nil(t, resp.DS["value"])
+}
+
+func TestGNMISyncResponse(t *testing.T) {
+	var (
+		addr = "127.0.0.1:50060"
+		ch   = make(telemetry.ExtDSChan, 10)
+	)
+
+	ln, err := mock.StartGNMIServer(addr, mock.Poll{Notification: mock.AristaUpdate()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	cfg := config.NewMockConfig()
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	sensors := []*config.Sensor{
+		{
+			Service: "gnmi",
+			Output:  "console::stdout",
+			Path:    "/interfaces/interface/state/counters",
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	g := New(cfg.Logger(), conn, sensors, ch)
+	go g.Start(ctx)
+
+	for _, operation := range []interface{}{nil, telemetry.OperationSync} {
+		select {
+		case resp := <-ch:
+			assert.Equal(t, operation, resp.DS["operation"])
+			assert.Equal(t, "/interfaces/interface/state/counters", resp.DS["prefix"])
+		case <-ctx.Done():
+			t.Fatal("timeout")
+		}
+	}
+}
@@ -0,0 +1,138 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package gnmi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/yahoo/panoptes-stream/telemetry"
+)
+
+// maxExactFloat is the largest integer which a float64 represents without loss (2^53).
+const maxExactFloat = 1 << 53
+
+// defaultCounter64Leaves are the 64-bit counter leaves once they're not configured,
+// a leaf starts with - matches the name suffix otherwise the whole name.
+var defaultCounter64Leaves = []string{"-octets", "-packets", "-pkts", "-bytes", "-errors", "-discards",
+	"-drops", "-transitions", "-count", "-64", "counter", "last-change"}
+
+var counter64Leaves atomic.Value
+
+// SetCounter64Leaves sets the 64-bit counter leaves (global counter64Leaves), the
+// RFC 7951 encodes the 64-bit numbers as string and the schema isn't available
+// to find them. the defaults apply once the leaves are empty.
+func SetCounter64Leaves(leaves []string) {
+	if len(leaves) < 1 {
+		leaves = defaultCounter64Leaves
+	}
+
+	counter64Leaves.Store(leaves)
+}
+
+// GetJSONValue returns the update value, the JSON and JSON_IETF numbers are
+// converted to number. the strings are converted once they belong to the
+// 64-bit counter leaves or they're integers beyond the float64 precision as
+// they can't be anything else. the leaf is the name of the value e.g. in-octets.
+func GetJSONValue(tv *gpb.TypedValue, leaf string) (interface{}, error) {
+	var (
+		jsondata []byte
+		value    interface{}
+	)
+
+	switch tv.Value.(type) {
+	case *gpb.TypedValue_JsonIetfVal:
+		jsondata = tv.GetJsonIetfVal()
+	case *gpb.TypedValue_JsonVal:
+		jsondata = tv.GetJsonVal()
+	default:
+		return telemetry.GetValue(tv)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsondata))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return convNumber(leaf, value), nil
+}
+
+func convNumber(leaf string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		return numberValue(v)
+	case string:
+		return stringValue(leaf, v)
+	case map[string]interface{}:
+		for key, val := range v {
+			v[key] = convNumber(trimModule(key), val)
+		}
+	case []interface{}:
+		for i, val := range v {
+			v[i] = convNumber(leaf, val)
+		}
+	}
+
+	return value
+}
+
+func numberValue(n json.Number) interface{} {
+	if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+		return u
+	}
+
+	if i, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+		return i
+	}
+
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+
+	return n.String()
+}
+
+func stringValue(leaf, s string) interface{} {
+	counter := isCounter64(leaf)
+
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil && (counter || u > maxExactFloat) {
+		return u
+	}
+
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil && (counter || i < -maxExactFloat) {
+		return i
+	}
+
+	return s
+}
+
+func isCounter64(leaf string) bool {
+	leaves, ok := counter64Leaves.Load().([]string)
+	if !ok {
+		leaves = defaultCounter64Leaves
+	}
+
+	for _, l := range leaves {
+		if l == leaf || (strings.HasPrefix(l, "-") && strings.HasSuffix(leaf, l)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// leafName returns the last path element name without module name.
+func leafName(path []*gpb.PathElem) string {
+	if len(path) < 1 {
+		return ""
+	}
+
+	return trimModule(path[len(path)-1].Name)
+}
@@ -0,0 +1,82 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package gnmi
+
+import (
+	"testing"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+)
+
+func jsonIetf(s string) *gpb.TypedValue {
+	return &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(s)}}
+}
+
+func TestGetJSONValue(t *testing.T) {
+	v, err := GetJSONValue(jsonIetf(`"18446744073709551615"`), "in-octets")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(18446744073709551615), v)
+
+	v, err = GetJSONValue(jsonIetf(`-5`), "mtu")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-5), v)
+
+	v, err = GetJSONValue(jsonIetf(`2.5`), "temperature")
+	assert.NoError(t, err)
+	assert.Equal(t, 2.5, v)
+
+	// the strings of the other leaves aren't converted
+	v, err = GetJSONValue(jsonIetf(`"0012"`), "description")
+	assert.NoError(t, err)
+	assert.Equal(t, "0012", v)
+
+	v, err = GetJSONValue(jsonIetf(`{"description":"100","in-octets":"100","mtu":1500,"out-pkts":["1","2"]}`), "counters")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"description": "100",
+		"in-octets":   uint64(100),
+		"mtu":         uint64(1500),
+		"out-pkts":    []interface{}{uint64(1), uint64(2)},
+	}, v)
+
+	// the integers beyond the float64 precision are converted regardless of the leaf
+	v, err = GetJSONValue(jsonIetf(`{"version":"12","name":"9007199254740993","offset":"-9007199254740993"}`), "state")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"version": "12",
+		"name":    uint64(9007199254740993),
+		"offset":  int64(-9007199254740993),
+	}, v)
+
+	v, err = GetJSONValue(jsonIetf(`{"in-bytes":"10","frame-count":"2","counter":"3","last-change":"4","encounter":"5"}`), "state")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"in-bytes":    uint64(10),
+		"frame-count": uint64(2),
+		"counter":     uint64(3),
+		"last-change": uint64(4),
+		"encounter":   "5",
+	}, v)
+
+	v, err = GetJSONValue(&gpb.TypedValue{Value: &gpb.TypedValue_UintVal{UintVal: 5}}, "in-octets")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), v)
+
+	_, err = GetJSONValue(jsonIetf(`{`), "")
+	assert.Error(t, err)
+}
+
+func TestSetCounter64Leaves(t *testing.T) {
+	SetCounter64Leaves([]string{"-octets", "uptime"})
+	defer SetCounter64Leaves(nil)
+
+	v, err := GetJSONValue(jsonIetf(`{"in-octets":"1","uptime":"2","in-pkts":"3"}`), "state")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"in-octets": uint64(1),
+		"uptime":    uint64(2),
+		"in-pkts":   "3",
+	}, v)
+}
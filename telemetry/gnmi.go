@@ -0,0 +1,212 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/status"
+)
+
+// defaultPollInterval is the poll and once interval if the sensor has no poll interval.
+const defaultPollInterval = 60
+
+// GNMISubscriptionGroup represents the gNMI subscriptions with
+// the same list mode (stream, poll or once) and poll interval.
+type GNMISubscriptionGroup struct {
+	Mode          gpb.SubscriptionList_Mode
+	Interval      time.Duration
+	Subscriptions []*gpb.Subscription
+	// Paths are the subscriptions' sensor paths respectively.
+	Paths []string
+}
+
+// GNMIResponse represents a subscribe response and the subscription
+// group which the response belongs to e.g. the group's sync_response.
+type GNMIResponse struct {
+	*gpb.SubscribeResponse
+	Group *GNMISubscriptionGroup
+}
+
+// GetGNMISubscriptionGroups groups the sensors by list mode and poll interval,
+// each group subscribes over a separate stream on the same connection.
+func GetGNMISubscriptionGroups(sensors []*config.Sensor) []*GNMISubscriptionGroup {
+	var (
+		groups []*GNMISubscriptionGroup
+		index  = make(map[string]*GNMISubscriptionGroup)
+	)
+
+	for _, sensor := range sensors {
+		mode := gpb.SubscriptionList_Mode(gpb.SubscriptionList_Mode_value[strings.ToUpper(sensor.ListMode)])
+
+		var interval time.Duration
+		if mode != gpb.SubscriptionList_STREAM {
+			interval = time.Duration(sensor.PollInterval) * time.Second
+			if interval <= 0 {
+				interval = defaultPollInterval * time.Second
+			}
+		}
+
+		key := fmt.Sprintf("%s/%s", mode, interval)
+		if _, ok := index[key]; !ok {
+			index[key] = &GNMISubscriptionGroup{Mode: mode, Interval: interval}
+			groups = append(groups, index[key])
+		}
+
+		index[key].Subscriptions = append(index[key].Subscriptions, GetGNMISubscriptions([]*config.Sensor{sensor})...)
+		index[key].Paths = append(index[key].Paths, sensor.Path)
+	}
+
+	return groups
+}
+
+// GNMISubscribe subscribes the subscription groups and sends the responses to the
+// data channel. the poll groups are polled per interval over their streams and the once
+// groups are re-subscribed per interval. it returns once a group terminated or there is no group.
+func GNMISubscribe(ctx context.Context, client gpb.GNMIClient, encoding gpb.Encoding, groups []*GNMISubscriptionGroup,
+	dataChan chan GNMIResponse, counter status.Metrics) error {
+	var errChan = make(chan error, len(groups))
+
+	if len(groups) < 1 {
+		return errors.New("subscription not found")
+	}
+
+	// terminates the rest of groups once a group terminated
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for _, group := range groups {
+		go func(group *GNMISubscriptionGroup) {
+			switch group.Mode {
+			case gpb.SubscriptionList_ONCE:
+				errChan <- gnmiOnce(ctx, client, encoding, group, dataChan, counter)
+			default:
+				errChan <- gnmiStream(ctx, client, encoding, group, dataChan, counter)
+			}
+		}(group)
+	}
+
+	return <-errChan
+}
+
+func gnmiStream(ctx context.Context, client gpb.GNMIClient, encoding gpb.Encoding, group *GNMISubscriptionGroup,
+	dataChan chan GNMIResponse, counter status.Metrics) error {
+	subClient, err := client.Subscribe(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = subClient.Send(getSubscribeRequest(encoding, group))
+	if err != nil {
+		return err
+	}
+
+	if group.Mode == gpb.SubscriptionList_POLL {
+		go gnmiPoll(ctx, subClient, group.Interval)
+	}
+
+	return gnmiRecv(ctx, subClient, group, dataChan, counter, false)
+}
+
+// gnmiPoll sends a poll request per interval, it stops once the
+// stream failed and the receiver returns the stream error.
+func gnmiPoll(ctx context.Context, subClient gpb.GNMI_SubscribeClient, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := subClient.Send(&gpb.SubscribeRequest{
+				Request: &gpb.SubscribeRequest_Poll{
+					Poll: &gpb.Poll{},
+				},
+			})
+			if err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func gnmiOnce(ctx context.Context, client gpb.GNMIClient, encoding gpb.Encoding, group *GNMISubscriptionGroup,
+	dataChan chan GNMIResponse, counter status.Metrics) error {
+	for {
+		sCtx, cancel := context.WithCancel(ctx)
+		subClient, err := client.Subscribe(sCtx)
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		if err = subClient.Send(getSubscribeRequest(encoding, group)); err == nil {
+			err = gnmiRecv(sCtx, subClient, group, dataChan, counter, true)
+		}
+		cancel()
+
+		if err != nil || ctx.Err() != nil {
+			return err
+		}
+
+		select {
+		case <-time.After(group.Interval):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// gnmiRecv receives the responses of the group till the stream terminated,
+// the once mode returns once the sync response received.
+func gnmiRecv(ctx context.Context, subClient gpb.GNMI_SubscribeClient, group *GNMISubscriptionGroup,
+	dataChan chan GNMIResponse, counter status.Metrics, once bool) error {
+	for {
+		resp, err := subClient.Recv()
+		if once && err == io.EOF {
+			return nil
+		}
+
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		select {
+		case dataChan <- GNMIResponse{SubscribeResponse: resp, Group: group}:
+			counter.Inc()
+		case <-ctx.Done():
+			return nil
+		}
+
+		if once && resp.GetSyncResponse() {
+			return nil
+		}
+	}
+}
+
+func getSubscribeRequest(encoding gpb.Encoding, group *GNMISubscriptionGroup) *gpb.SubscribeRequest {
+	return &gpb.SubscribeRequest{
+		Request: &gpb.SubscribeRequest_Subscribe{
+			Subscribe: &gpb.SubscriptionList{
+				Mode:         group.Mode,
+				Encoding:     encoding,
+				Subscription: group.Subscriptions,
+				UpdatesOnly:  false,
+			},
+		},
+	}
+}
@@ -0,0 +1,105 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/status"
+	"github.com/yahoo/panoptes-stream/telemetry/mock"
+)
+
+func TestGetGNMISubscriptionGroups(t *testing.T) {
+	sensors := []*config.Sensor{
+		{Path: "/interfaces/interface/state/counters", Mode: "sample", SampleInterval: 10},
+		{Path: "/components/component", ListMode: "once", PollInterval: 3600},
+		{Path: "/interfaces/interface/state/oper-status", ListMode: "POLL", PollInterval: 30},
+		{Path: "/network-instances/network-instance", ListMode: "stream"},
+		{Path: "/system/state", ListMode: "poll"},
+		{Path: "/lldp/interfaces", ListMode: "poll", PollInterval: 30},
+	}
+
+	groups := GetGNMISubscriptionGroups(sensors)
+	assert.Len(t, groups, 4)
+
+	assert.Equal(t, gpb.SubscriptionList_STREAM, groups[0].Mode)
+	assert.Equal(t, time.Duration(0), groups[0].Interval)
+	assert.Len(t, groups[0].Subscriptions, 2)
+	assert.Equal(t, uint64(10*time.Second), groups[0].Subscriptions[0].SampleInterval)
+
+	assert.Equal(t, gpb.SubscriptionList_ONCE, groups[1].Mode)
+	assert.Equal(t, time.Hour, groups[1].Interval)
+
+	assert.Equal(t, gpb.SubscriptionList_POLL, groups[2].Mode)
+	assert.Equal(t, 30*time.Second, groups[2].Interval)
+	assert.Len(t, groups[2].Subscriptions, 2)
+	assert.Equal(t, []string{"/interfaces/interface/state/oper-status", "/lldp/interfaces"}, groups[2].Paths)
+
+	assert.Equal(t, gpb.SubscriptionList_POLL, groups[3].Mode)
+	assert.Equal(t, defaultPollInterval*time.Second, groups[3].Interval)
+}
+
+func testGNMISubscribe(t *testing.T, addr string, sensors []*config.Sensor) {
+	ln, err := mock.StartGNMIServer(addr, mock.Poll{Notification: mock.AristaUpdate()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	dataChan := make(chan GNMIResponse, 10)
+	counter := status.NewCounter("gnmi_subscribe_test_total", "")
+	groups := GetGNMISubscriptionGroups(sensors)
+
+	go GNMISubscribe(ctx, gpb.NewGNMIClient(conn), gpb.Encoding_PROTO, groups, dataChan, counter)
+
+	// initial update + re-issued update, each followed by a sync response
+	for i := 0; i < 4; i++ {
+		select {
+		case resp := <-dataChan:
+			assert.Equal(t, groups[0], resp.Group)
+			if i%2 == 0 {
+				assert.Equal(t, mock.AristaUpdate().Timestamp, resp.GetUpdate().GetTimestamp())
+			} else {
+				assert.True(t, resp.GetSyncResponse())
+			}
+		case <-ctx.Done():
+			t.Fatal("timeout")
+		}
+	}
+}
+
+func TestGNMISubscribeNoGroup(t *testing.T) {
+	counter := status.NewCounter("gnmi_subscribe_test_total", "")
+
+	err := GNMISubscribe(context.Background(), nil, gpb.Encoding_PROTO, nil, nil, counter)
+	assert.Error(t, err)
+}
+
+func TestGNMISubscribePoll(t *testing.T) {
+	testGNMISubscribe(t, "127.0.0.1:50056", []*config.Sensor{
+		{Path: "/interfaces/interface", ListMode: "poll", PollInterval: 1},
+	})
+}
+
+func TestGNMISubscribeOnce(t *testing.T) {
+	testGNMISubscribe(t, "127.0.0.1:50057", []*config.Sensor{
+		{Path: "/interfaces/interface", ListMode: "once", PollInterval: 1},
+	})
+}
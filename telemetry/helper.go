@@ -6,6 +6,7 @@ package telemetry
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"strings"
@@ -16,6 +17,13 @@ import (
 	"github.com/yahoo/panoptes-stream/config"
 )
 
+const (
+	// OperationDelete represents a deleted path (gNMI delete notification).
+	OperationDelete = "delete"
+	// OperationSync represents the end of the initial state (gNMI sync_response).
+	OperationSync = "sync"
+)
+
 // GetKey returns telemetry key and extracted labels.
 func GetKey(buf *bytes.Buffer, path []*gpb.PathElem) (string, map[string]string) {
 	labels := make(map[string]string)
@@ -107,6 +115,41 @@ func GetGNMISubscriptions(sensors []*config.Sensor) []*gpb.Subscription {
 	return subscriptions
 }
 
+// SyncResponse sends a sync event per sensor path of the subscription group once the device
+// sent the group's sync_response, the downstream consumers can distinguish the initial state
+// from the live changes.
+func SyncResponse(outChan ExtDSChan, pathOutput map[string]string, paths []string, systemID string) error {
+	for _, path := range paths {
+		path = strings.TrimSuffix(path, "/")
+
+		output, ok := pathOutput[path+"/"]
+		if !ok {
+			continue
+		}
+
+		ds := DataStore{
+			"prefix":    path,
+			"labels":    map[string]string{},
+			"timestamp": time.Now().UnixNano(),
+			"system_id": systemID,
+			"key":       "sync_response",
+			"value":     true,
+			"operation": OperationSync,
+		}
+
+		select {
+		case outChan <- ExtDataStore{
+			DS:     ds,
+			Output: output,
+		}:
+		default:
+			return errors.New("dataset drop")
+		}
+	}
+
+	return nil
+}
+
 // GetPathOutput returns path to output map.
 func GetPathOutput(sensors []*config.Sensor) map[string]string {
 	var pathOutput = make(map[string]string)
@@ -135,7 +178,8 @@ func getLeafList(elems []*gpb.TypedValue) (interface{}, error) {
 	return list, nil
 }
 
-// MergeLabels merges key labels with prefix labels.
+// MergeLabels merges key labels with prefix labels, the key label takes precedence
+// on collision and the prefix label is kept under its full path e.g. prefix/name.
 func MergeLabels(keyLabels, prefixLabels map[string]string, prefix string) map[string]string {
 	if len(keyLabels) > 0 {
 		for k, v := range prefixLabels {
@@ -172,7 +216,7 @@ func getPathWithoutKey(path string) (string, error) {
 }
 
 // getSensorsPerService splits sensors if they have overlap with each other.
-// arista.gnmi and cisco.gnmi can not distinguish between overlapped
+// arista.gnmi, cisco.gnmi and gnmi can not distinguish between overlapped
 // sensors once the metrics returned from devices (multi path use case)
 // the only way to distinguish them is split them to different grpc connections.
 func getSensorsPerService(deviceSensors map[string][]*config.Sensor) (map[string][]*config.Sensor, error) {
@@ -184,7 +228,7 @@ func getSensorsPerService(deviceSensors map[string][]*config.Sensor) (map[string
 	for service, sensors := range deviceSensors {
 		paths := map[string]bool{}
 
-		if service == "arista.gnmi" || service == "cisco.gnmi" {
+		if service == "arista.gnmi" || service == "cisco.gnmi" || service == "gnmi" {
 			for _, sensor := range sensors {
 				ps, err := getPathWithoutKey(sensor.Path)
 				if err != nil {
@@ -205,3 +205,32 @@ func TestMergeLabel(t *testing.T) {
 
 	assert.Equal(t, exp, m)
 }
+
+func TestSyncResponse(t *testing.T) {
+	ch := make(ExtDSChan, 3)
+	pathOutput := map[string]string{
+		"/interfaces/interface/": "console::stdout",
+		"/components/component/": "console::stderr",
+		"/system/state/":         "console::stdout",
+	}
+	paths := []string{"/interfaces/interface", "/components/component/"}
+
+	// the sync events belong to the subscription group's paths
+	err := SyncResponse(ch, pathOutput, paths, "127.0.0.1")
+	assert.NoError(t, err)
+	assert.Len(t, ch, 2)
+
+	for i := 0; i < 2; i++ {
+		resp := <-ch
+		assert.Equal(t, OperationSync, resp.DS["operation"])
+		assert.Equal(t, "127.0.0.1", resp.DS["system_id"])
+		assert.Equal(t, true, resp.DS["value"])
+		assert.NotEqual(t, "/system/state", resp.DS["prefix"])
+		assert.Equal(t, pathOutput[resp.DS["prefix"].(string)+"/"], resp.Output)
+	}
+
+	// channel is full
+	ch = make(ExtDSChan, 1)
+	err = SyncResponse(ch, pathOutput, paths, "127.0.0.1")
+	assert.Error(t, err)
+}
@@ -30,9 +30,9 @@ var gnmiVersion = "0.7.0"
 // GNMI represents a GNMI Juniper.
 type GNMI struct {
 	conn          *grpc.ClientConn
-	subscriptions []*gpb.Subscription
+	subscriptions []*telemetry.GNMISubscriptionGroup
 
-	dataChan chan *gpb.SubscribeResponse
+	dataChan chan telemetry.GNMIResponse
 	outChan  telemetry.ExtDSChan
 	logger   *zap.Logger
 
@@ -55,9 +55,9 @@ func New(logger *zap.Logger, conn *grpc.ClientConn, sensors []*config.Sensor, ou
 	return &GNMI{
 		logger:        logger,
 		conn:          conn,
-		subscriptions: telemetry.GetGNMISubscriptions(sensors),
+		subscriptions: telemetry.GetGNMISubscriptionGroups(sensors),
 		pathOutput:    telemetry.GetPathOutput(sensors),
-		dataChan:      make(chan *gpb.SubscribeResponse, 100),
+		dataChan:      make(chan telemetry.GNMIResponse, 100),
 		outChan:       outChan,
 		metrics:       metrics,
 	}
@@ -67,47 +67,19 @@ func New(logger *zap.Logger, conn *grpc.ClientConn, sensors []*config.Sensor, ou
 func (g *GNMI) Start(ctx context.Context) error {
 	defer status.Unregister(status.Labels{"host": g.conn.Target()}, g.metrics)
 
-	client := gpb.NewGNMIClient(g.conn)
-	subReq := &gpb.SubscribeRequest{
-		Request: &gpb.SubscribeRequest_Subscribe{
-			Subscribe: &gpb.SubscriptionList{
-				Mode:         gpb.SubscriptionList_STREAM,
-				Encoding:     gpb.Encoding(gpb.Encoding_value["PROTO"]),
-				Subscription: g.subscriptions,
-				UpdatesOnly:  false,
-			},
-		},
-	}
-
-	subClient, err := client.Subscribe(ctx)
-	if err != nil {
-		return err
-	}
-
-	err = subClient.Send(subReq)
-	if err != nil {
-		return err
-	}
-
 	workers := config.GetEnvInt("JUNIPER_GNMI_WORKERS", 1)
 	for i := 0; i < workers; i++ {
 		go g.worker(ctx)
 	}
 
-	for {
-		resp, err := subClient.Recv()
-		if err != nil && ctx.Err() == nil {
-			return err
-		}
-
-		if ctx.Err() != nil {
-			return nil
-		}
+	client := gpb.NewGNMIClient(g.conn)
 
-		g.dataChan <- resp
-		g.metrics["gRPCDataTotal"].Inc()
+	encoding, subscriptions, err := telemetry.GNMICapabilitiesHandshake(ctx, client, g.conn.Target(), "juniper.gnmi", gpb.Encoding(gpb.Encoding_value["PROTO"]), g.subscriptions, g.logger)
+	if err != nil {
+		return err
 	}
 
+	return telemetry.GNMISubscribe(ctx, client, encoding, subscriptions, g.dataChan, g.metrics["gRPCDataTotal"])
 }
 func (g *GNMI) worker(ctx context.Context) {
 	var (
@@ -125,6 +97,14 @@ func (g *GNMI) worker(ctx context.Context) {
 
 			start = time.Now()
 
+			if d.GetSyncResponse() {
+				if err := telemetry.SyncResponse(g.outChan, g.pathOutput, d.Group.Paths, systemID); err != nil {
+					g.metrics["dropsTotal"].Inc()
+					g.logger.Error("juniper.gnmi", zap.Error(err))
+				}
+				continue
+			}
+
 			resp, ok := d.Response.(*gpb.SubscribeResponse_Update)
 			if !ok {
 				continue
@@ -210,9 +190,57 @@ func (g *GNMI) datastore(buf *bytes.Buffer, resp *gpb.SubscribeResponse_Update,
 
 	}
 
+	if len(resp.Update.Delete) > 0 && output == "" {
+		output, ok = g.getOutput(prefix)
+		if !ok {
+			return fmt.Errorf("out not found - %s", prefix)
+		}
+	}
+
+	for _, path := range resp.Update.Delete {
+		buf.Reset()
+
+		key, keyLabels := getKey(buf, path.Elem)
+		labels = telemetry.MergeLabels(keyLabels, prefixLabels, prefix)
+
+		dataStore := telemetry.DataStore{
+			"prefix":    prefix,
+			"labels":    labels,
+			"timestamp": resp.Update.GetTimestamp(),
+			"system_id": systemID,
+			"key":       key,
+			"value":     nil,
+			"operation": telemetry.OperationDelete,
+		}
+
+		select {
+		case g.outChan <- telemetry.ExtDataStore{
+			DS:     dataStore,
+			Output: output,
+		}:
+		default:
+			g.metrics["dropsTotal"].Inc()
+			g.logger.Warn("juniper.gnmi", zap.String("error", "dataset drop"))
+		}
+	}
+
 	return nil
 }
 
+// getOutput returns the output of the longest sensor path which
+// matches the prefix, the delete notifications have no telemetry header.
+func (g *GNMI) getOutput(prefix string) (string, bool) {
+	var path, output string
+
+	for p, o := range g.pathOutput {
+		if strings.HasPrefix(prefix+"/", p) && len(p) > len(path) {
+			path, output = p, o
+		}
+	}
+
+	return output, path != ""
+}
+
 func getPrefix(buf *bytes.Buffer, path []*gpb.PathElem) (string, map[string]string) {
 	labels := make(map[string]string)
 
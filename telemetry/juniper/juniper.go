@@ -5,8 +5,10 @@ package juniper
 
 import (
 	"github.com/yahoo/panoptes-stream/telemetry"
+	"github.com/yahoo/panoptes-stream/telemetry/dialout"
 	"github.com/yahoo/panoptes-stream/telemetry/juniper/gnmi"
 	"github.com/yahoo/panoptes-stream/telemetry/juniper/jti"
+	"github.com/yahoo/panoptes-stream/telemetry/juniper/native"
 )
 
 // Register Juniper telemetries
@@ -14,3 +16,8 @@ func Register(telemetryRegistrar *telemetry.Registrar) {
 	telemetryRegistrar.Register("juniper.gnmi", gnmi.Version(), gnmi.New)
 	telemetryRegistrar.Register("juniper.jti", jti.Version(), jti.New)
 }
+
+// RegisterDialout Juniper dial-out telemetries
+func RegisterDialout(dialoutRegistrar *dialout.Registrar) {
+	dialoutRegistrar.Register("juniper.native", native.Version(), native.NewDialout)
+}
@@ -0,0 +1,353 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package native
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/status"
+	"github.com/yahoo/panoptes-stream/telemetry"
+	"github.com/yahoo/panoptes-stream/telemetry/dialout"
+	tpb "github.com/yahoo/panoptes-stream/telemetry/juniper/proto/telemetry_top"
+)
+
+var nativeVersion = "1.0"
+
+// dialoutService is the sensor service name which binds
+// a device's sensor paths to the outputs.
+const dialoutService = "juniper.native.dialout"
+
+const maxDatagramLength = 65535
+
+// keys are the sensors fields which they're converted to labels
+var keys = map[string]bool{
+	"if_name":             true,
+	"queue_number":        true,
+	"filter_name":         true,
+	"name":                true,
+	"instance_identifier": true,
+	"counter_name":        true,
+}
+
+// Native represents Junos native sensors over UDP.
+type Native struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	cfg        config.Config
+	conf       config.DialoutService
+	dataChan   chan nativeData
+	outChan    telemetry.ExtDSChan
+	logger     *zap.Logger
+	metrics    map[string]status.Metrics
+	peerOutput map[string]map[string]string
+	hosts      map[string]string
+
+	sync.RWMutex
+}
+
+type nativeData struct {
+	host string
+	data []byte
+}
+
+// NewDialout returns a new instance of Junos native sensors collector.
+func NewDialout(ctx context.Context, cfg config.Config, conf config.DialoutService, outChan telemetry.ExtDSChan) dialout.Service {
+	var metrics = make(map[string]status.Metrics)
+
+	metrics["dataTotal"] = status.NewCounter("juniper_native_data_total", "")
+	metrics["dropsTotal"] = status.NewCounter("juniper_native_drops_total", "")
+	metrics["errorsTotal"] = status.NewCounter("juniper_native_errors_total", "")
+
+	status.Register(status.Labels{"service": conf.Name}, metrics)
+
+	n := &Native{
+		cfg:      cfg,
+		conf:     conf,
+		outChan:  outChan,
+		logger:   cfg.Logger(),
+		dataChan: make(chan nativeData, 1000),
+		metrics:  metrics,
+	}
+
+	n.peerOutput, n.hosts = getOutputs(cfg)
+	n.ctx, n.cancel = context.WithCancel(ctx)
+
+	return n
+}
+
+// Start creates workers and starts the UDP listener.
+func (n *Native) Start() error {
+	var conf = n.conf
+
+	if conf.Addr == "" {
+		return errors.New("address is empty")
+	}
+
+	if conf.Workers < 1 {
+		conf.Workers = 2
+	}
+
+	conn, err := net.ListenPacket("udp", conf.Addr)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < conf.Workers; i++ {
+		go n.worker()
+	}
+
+	go func() {
+		<-n.ctx.Done()
+		conn.Close()
+	}()
+
+	go n.serve(conn)
+
+	n.logger.Info("juniper.native", zap.String("address", conf.Addr))
+
+	return nil
+}
+
+// Stop stops the listener and workers.
+func (n *Native) Stop() {
+	n.cancel()
+
+	status.Unregister(status.Labels{"service": n.conf.Name}, n.metrics)
+}
+
+// Update updates the devices' outputs once the configuration changed.
+func (n *Native) Update() {
+	n.Lock()
+	defer n.Unlock()
+
+	n.peerOutput, n.hosts = getOutputs(n.cfg)
+}
+
+func (n *Native) serve(conn net.PacketConn) {
+	var buf = make([]byte, maxDatagramLength)
+
+	for {
+		size, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if n.ctx.Err() == nil {
+				n.logger.Error("juniper.native", zap.Error(err))
+			}
+			return
+		}
+
+		host, _, _ := net.SplitHostPort(addr.String())
+		data := make([]byte, size)
+		copy(data, buf[:size])
+
+		select {
+		case n.dataChan <- nativeData{host: host, data: data}:
+			n.metrics["dataTotal"].Inc()
+		default:
+			n.metrics["dropsTotal"].Inc()
+		}
+	}
+}
+
+// regxPath matches the sensor path at the sensor name
+// e.g. sensor_1000:/junos/system/linecard/interface/:/junos/system/linecard/interface/:PFE
+var regxPath = regexp.MustCompile(`:(/[^:]*/):`)
+
+func (n *Native) worker() {
+	for {
+		select {
+		case d, ok := <-n.dataChan:
+			if !ok {
+				return
+			}
+
+			if err := n.datastore(regxPath, d); err != nil {
+				n.metrics["errorsTotal"].Inc()
+				n.logger.Error("juniper.native", zap.String("peer", d.host), zap.Error(err))
+			}
+
+		case <-n.ctx.Done():
+			return
+		}
+	}
+}
+
+func (n *Native) datastore(regxPath *regexp.Regexp, d nativeData) error {
+	ts := &tpb.TelemetryStream{}
+	if err := proto.Unmarshal(d.data, ts); err != nil {
+		return err
+	}
+
+	path := regxPath.FindStringSubmatch(ts.GetSensorName())
+	if len(path) < 2 {
+		return fmt.Errorf("path not found - %s", ts.GetSensorName())
+	}
+
+	output, err := n.getOutput(d.host, path[1])
+	if err != nil {
+		return err
+	}
+
+	jnpr, err := proto.GetExtension(ts.GetEnterprise(), tpb.E_JuniperNetworks)
+	if err != nil {
+		return errors.New("juniper networks sensors not found")
+	}
+
+	var (
+		prefix    = strings.TrimSuffix(path[1], "/")
+		timestamp = ts.GetTimestamp() * 1000000
+		dropped   bool
+	)
+
+	walk(proto.MessageReflect(jnpr.(*tpb.JuniperNetworksSensors)), "", false, map[string]string{}, func(labels map[string]string, key string, value interface{}) {
+		ds := telemetry.DataStore{
+			"prefix":    prefix,
+			"labels":    labels,
+			"timestamp": timestamp,
+			"system_id": ts.GetSystemId(),
+			"key":       key,
+			"value":     value,
+		}
+
+		select {
+		case n.outChan <- telemetry.ExtDataStore{
+			DS:     ds,
+			Output: output,
+		}:
+		default:
+			n.metrics["dropsTotal"].Inc()
+			dropped = true
+		}
+	})
+
+	if dropped {
+		return errors.New("dataset drop")
+	}
+
+	return nil
+}
+
+// walk flattens a message to the key values. the key fields become labels
+// and once a message has key fields (entry) the key is built from its
+// nested fields names e.g. ingress_stats/if_octets labeled by if_name.
+func walk(m protoreflect.Message, key string, entry bool, labels map[string]string, emit func(map[string]string, string, interface{})) {
+	var (
+		mLabels = labels
+		isEntry bool
+	)
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if name := string(fd.Name()); keys[name] && !fd.IsList() {
+			if !isEntry {
+				mLabels = copyLabels(labels)
+				isEntry = true
+			}
+			mLabels[name] = fmt.Sprint(v.Interface())
+		}
+		return true
+	})
+
+	entry = entry || isEntry
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := string(fd.Name())
+		if keys[name] {
+			return true
+		}
+
+		childKey := ""
+		if entry {
+			childKey = join(key, name)
+		}
+
+		switch {
+		case fd.IsList() && fd.Kind() == protoreflect.MessageKind:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				walk(list.Get(i).Message(), childKey, entry, mLabels, emit)
+			}
+		case fd.IsList():
+			// repeated scalars are not supported
+		case fd.Kind() == protoreflect.MessageKind:
+			walk(v.Message(), childKey, entry, mLabels, emit)
+		case entry:
+			emit(mLabels, childKey, v.Interface())
+		}
+
+		return true
+	})
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	var c = make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		c[k] = v
+	}
+
+	return c
+}
+
+func join(key, name string) string {
+	if key == "" {
+		return name
+	}
+
+	return key + "/" + name
+}
+
+// getOutput returns the output of the sensor path, the device's
+// sensors take precedence over the global default output.
+func (n *Native) getOutput(host, path string) (string, error) {
+	n.RLock()
+	defer n.RUnlock()
+
+	if h, ok := n.hosts[host]; ok {
+		host = h
+	}
+
+	if output, ok := n.peerOutput[host][path]; ok {
+		return output, nil
+	}
+
+	if n.cfg.Global().Dialout.DefaultOutput != "" {
+		return n.cfg.Global().Dialout.DefaultOutput, nil
+	}
+
+	return "", fmt.Errorf("output not found - %s", path)
+}
+
+// getOutputs returns the devices' sensor path to output
+// and the resolved peer address to host.
+func getOutputs(cfg config.Config) (map[string]map[string]string, map[string]string) {
+	var peerOutput = make(map[string]map[string]string)
+
+	devices, hosts := dialout.GetDevices(cfg.Devices(), dialoutService)
+	for host, device := range devices {
+		peerOutput[host] = make(map[string]string)
+		for _, sensor := range device.Sensors[dialoutService] {
+			if strings.HasSuffix(sensor.Path, "/") {
+				peerOutput[host][sensor.Path] = sensor.Output
+			} else {
+				peerOutput[host][fmt.Sprintf("%s/", sensor.Path)] = sensor.Output
+			}
+		}
+	}
+
+	return peerOutput, hosts
+}
+
+// Version returns version
+func Version() string {
+	return nativeVersion
+}
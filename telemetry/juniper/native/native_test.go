@@ -0,0 +1,142 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package native
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/telemetry"
+	"github.com/yahoo/panoptes-stream/telemetry/mock"
+)
+
+func collect(ch telemetry.ExtDSChan) map[string]telemetry.ExtDataStore {
+	r := make(map[string]telemetry.ExtDataStore)
+	for len(ch) > 0 {
+		e := <-ch
+		r[e.DS["key"].(string)] = e
+	}
+
+	return r
+}
+
+func TestNativeInterface(t *testing.T) {
+	cfg := config.NewMockConfig()
+	cfg.Global().Dialout.DefaultOutput = "console::stdout"
+	ch := make(telemetry.ExtDSChan, 10)
+
+	n := NewDialout(context.Background(), cfg, config.DialoutService{}, ch).(*Native)
+	defer n.Stop()
+
+	b, err := proto.Marshal(mock.JuniperNativeInterface())
+	assert.NoError(t, err)
+
+	err = n.datastore(regxPath, nativeData{host: "127.0.0.1", data: b})
+	assert.NoError(t, err)
+
+	r := collect(ch)
+	assert.Len(t, r, 5)
+
+	resp := r["ingress_stats/if_octets"]
+	assert.Equal(t, "/junos/system/linecard/interface", resp.DS["prefix"])
+	assert.Equal(t, map[string]string{"if_name": "et-0/0/0"}, resp.DS["labels"])
+	assert.Equal(t, uint64(52613105736), resp.DS["value"])
+	assert.Equal(t, uint64(1596067993610000000), resp.DS["timestamp"])
+	assert.Equal(t, "core1.lax", resp.DS["system_id"])
+	assert.Equal(t, "console::stdout", resp.Output)
+
+	resp = r["egress_queue_info/packets"]
+	assert.Equal(t, map[string]string{"if_name": "et-0/0/0", "queue_number": "0"}, resp.DS["labels"])
+	assert.Equal(t, uint64(1024), resp.DS["value"])
+
+	assert.Equal(t, uint32(520), r["snmp_if_index"].DS["value"])
+	assert.Equal(t, "UP", r["if_operational_status"].DS["value"])
+}
+
+func TestNativeFirewallLSP(t *testing.T) {
+	cfg := config.NewMockConfig()
+	cfg.MDevices = []config.Device{
+		{
+			DeviceConfig: config.DeviceConfig{Host: "127.0.0.1"},
+			Sensors: map[string][]*config.Sensor{
+				"juniper.native.dialout": {
+					{Service: "juniper.native.dialout", Path: "/junos/system/linecard/firewall", Output: "kafka1::fw"},
+					{Service: "juniper.native.dialout", Path: "/junos/services/label-switched-path/usage/", Output: "kafka1::lsp"},
+				},
+			},
+		},
+	}
+	ch := make(telemetry.ExtDSChan, 10)
+
+	n := NewDialout(context.Background(), cfg, config.DialoutService{}, ch).(*Native)
+	defer n.Stop()
+
+	b, err := proto.Marshal(mock.JuniperNativeFirewall())
+	assert.NoError(t, err)
+
+	err = n.datastore(regxPath, nativeData{host: "127.0.0.1", data: b})
+	assert.NoError(t, err)
+
+	r := collect(ch)
+	assert.Len(t, r, 2)
+	assert.Equal(t, "kafka1::fw", r["counter_stats/packets"].Output)
+	assert.Equal(t, map[string]string{"filter_name": "protect-re", "name": "ssh"}, r["counter_stats/bytes"].DS["labels"])
+	assert.Equal(t, uint64(1200), r["counter_stats/bytes"].DS["value"])
+
+	b, err = proto.Marshal(mock.JuniperNativeLSP())
+	assert.NoError(t, err)
+
+	err = n.datastore(regxPath, nativeData{host: "127.0.0.1", data: b})
+	assert.NoError(t, err)
+
+	r = collect(ch)
+	assert.Len(t, r, 2)
+	assert.Equal(t, "kafka1::lsp", r["packets"].Output)
+	assert.Equal(t, "/junos/services/label-switched-path/usage", r["packets"].DS["prefix"])
+	assert.Equal(t, map[string]string{"name": "lax-sjc", "instance_identifier": "0", "counter_name": "c-1"}, r["packets"].DS["labels"])
+
+	// unknown device without default output
+	err = n.datastore(regxPath, nativeData{host: "127.0.0.2", data: b})
+	assert.Error(t, err)
+}
+
+func TestNativeStart(t *testing.T) {
+	var addr = "127.0.0.1:50530"
+
+	cfg := config.NewMockConfig()
+	cfg.Global().Dialout.DefaultOutput = "console::stdout"
+	ch := make(telemetry.ExtDSChan, 10)
+
+	n := NewDialout(context.Background(), cfg, config.DialoutService{Addr: addr, Workers: 1}, ch)
+	err := n.Start()
+	assert.NoError(t, err)
+	defer n.Stop()
+
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	b, err := proto.Marshal(mock.JuniperNativeLSP())
+	assert.NoError(t, err)
+	conn.Write(b)
+
+	select {
+	case resp := <-ch:
+		assert.Equal(t, "core1.lax", resp.DS["system_id"])
+	case <-time.After(time.Second * 2):
+		t.Fatal("timeout")
+	}
+}
+
+func TestVersion(t *testing.T) {
+	assert.Equal(t, nativeVersion, Version())
+}
@@ -5,7 +5,9 @@ package mock
 
 import (
 	"context"
+	"errors"
 	"net"
+	"sync"
 
 	"github.com/openconfig/gnmi/proto/gnmi"
 	"google.golang.org/grpc"
@@ -19,7 +21,9 @@ type Response interface {
 
 // GNMIServer represents gNMI server
 type GNMIServer struct {
-	Resp Response
+	Resp    Response
+	GetResp *gnmi.GetResponse
+	CapResp *gnmi.CapabilityResponse
 }
 
 // Update represents gNMI update
@@ -28,14 +32,23 @@ type Update struct {
 	Attempt      int
 }
 
-// Capabilities is a capabilities mock method
-func (*GNMIServer) Capabilities(context.Context, *gnmi.CapabilityRequest) (*gnmi.CapabilityResponse, error) {
-	return nil, nil
+// Capabilities is a capabilities mock method, it returns the configured
+// capabilities or an empty response (no validation at client).
+func (g *GNMIServer) Capabilities(context.Context, *gnmi.CapabilityRequest) (*gnmi.CapabilityResponse, error) {
+	if g.CapResp == nil {
+		return &gnmi.CapabilityResponse{}, nil
+	}
+
+	return g.CapResp, nil
 }
 
-// Get is a get mock method
-func (*GNMIServer) Get(context.Context, *gnmi.GetRequest) (*gnmi.GetResponse, error) {
-	return nil, nil
+// Get is a get mock method, it returns the configured get response.
+func (g *GNMIServer) Get(context.Context, *gnmi.GetRequest) (*gnmi.GetResponse, error) {
+	if g.GetResp == nil {
+		return nil, errors.New("get response not available")
+	}
+
+	return g.GetResp, nil
 }
 
 // Set is a set mock method
@@ -64,14 +77,63 @@ func (u Update) Run(server gnmi.GNMI_SubscribeServer) error {
 	return nil
 }
 
+// Poll represents gNMI poll and once responses
+type Poll struct {
+	Notification *gnmi.Notification
+}
+
+// Run replies the notification and the sync response per subscribe or poll
+// request, it terminates the stream after the first reply in the once mode.
+func (p Poll) Run(server gnmi.GNMI_SubscribeServer) error {
+	for {
+		req, err := server.Recv()
+		if err != nil {
+			return err
+		}
+
+		err = server.Send(&gnmi.SubscribeResponse{
+			Response: &gnmi.SubscribeResponse_Update{
+				Update: p.Notification,
+			}})
+		if err != nil {
+			return err
+		}
+
+		err = server.Send(&gnmi.SubscribeResponse{
+			Response: &gnmi.SubscribeResponse_SyncResponse{
+				SyncResponse: true,
+			}})
+		if err != nil {
+			return err
+		}
+
+		if req.GetSubscribe().GetMode() == gnmi.SubscriptionList_ONCE {
+			return nil
+		}
+	}
+}
+
 // StartGNMIServer starts gNMI mock server
 func StartGNMIServer(addr string, resp Response) (net.Listener, error) {
+	return startGNMIServer(addr, &GNMIServer{Resp: resp})
+}
+
+// StartGNMICapServer starts gNMI mock server with the given capabilities
+func StartGNMICapServer(addr string, resp Response, capResp *gnmi.CapabilityResponse) (net.Listener, error) {
+	return startGNMIServer(addr, &GNMIServer{Resp: resp, CapResp: capResp})
+}
+
+// StartGNMIGetServer starts gNMI mock server which it replies the get requests
+func StartGNMIGetServer(addr string, resp *gnmi.GetResponse) (net.Listener, error) {
+	return startGNMIServer(addr, &GNMIServer{GetResp: resp})
+}
+
+func startGNMIServer(addr string, mockServer *GNMIServer) (net.Listener, error) {
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
 		return nil, err
 	}
 	gServer := grpc.NewServer()
-	mockServer := &GNMIServer{resp}
 	gnmi.RegisterGNMIServer(gServer, mockServer)
 
 	go func() {
@@ -81,6 +143,43 @@ func StartGNMIServer(addr string, resp Response) (net.Listener, error) {
 	return ln, nil
 }
 
+// GetInterfaces returns gNMI get response included the JSON_IETF encoded interfaces
+func GetInterfaces() *gnmi.GetResponse {
+	return &gnmi.GetResponse{
+		Notification: []*gnmi.Notification{
+			{
+				Timestamp: 1595363593437180059,
+				Update: []*gnmi.Update{
+					{
+						Path: &gnmi.Path{
+							Elem: []*gnmi.PathElem{
+								{Name: "interfaces"},
+								{Name: "interface"},
+							},
+						},
+						Val: &gnmi.TypedValue{
+							Value: &gnmi.TypedValue_JsonIetfVal{
+								JsonIetfVal: []byte(`{
+									"openconfig-interfaces:interface": [
+										{
+											"name": "Ethernet1",
+											"config": {"name": "Ethernet1", "mtu": 9214},
+											"state": {
+												"oper-status": "UP",
+												"counters": {"in-octets": "50302030597", "out-octets": "12093"}
+											}
+										}
+									]
+								}`),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 // AristaUpdate returns gNMI notification included an Arista interface update
 func AristaUpdate() *gnmi.Notification {
 	return &gnmi.Notification{
@@ -281,3 +380,106 @@ func JuniperFakeDuplicateLabel() *gnmi.Notification {
 		},
 	}
 }
+
+// NokiaSROSPort returns a gNMI notification included a Nokia SR OS port statistics update
+func NokiaSROSPort() *gnmi.Notification {
+	return &gnmi.Notification{
+		Timestamp: 1602683312448512580,
+		Prefix: &gnmi.Path{
+			Elem: []*gnmi.PathElem{
+				{Name: "nokia-state:state"},
+				{Name: "port", Key: map[string]string{"port-id": "1/1/1"}},
+			},
+		},
+		Update: []*gnmi.Update{
+			{
+				Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "statistics"}, {Name: "in-octets"}}},
+				Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte("\"28342234521\"")}},
+			},
+			{
+				Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "statistics"}, {Name: "out-octets"}}},
+				Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte("\"1834236711\"")}},
+			},
+			{
+				Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "statistics"}, {Name: "in-octets-64"}}},
+				Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte("\"28342234521\"")}},
+			},
+		},
+	}
+}
+
+// NokiaSRLInterface returns a gNMI notification included a Nokia SR Linux interface statistics container
+func NokiaSRLInterface() *gnmi.Notification {
+	return &gnmi.Notification{
+		Timestamp: 1602683312448512580,
+		Prefix:    &gnmi.Path{},
+		Update: []*gnmi.Update{
+			{
+				Path: &gnmi.Path{
+					Elem: []*gnmi.PathElem{
+						{Name: "srl_nokia-interfaces:interface", Key: map[string]string{"name": "ethernet-1/1"}},
+						{Name: "statistics"},
+					},
+				},
+				Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{"in-octets":"1043","out-octets":"2391","srl_nokia-interfaces:carrier-transitions":"1","in-bytes":"1043","last-change":"1602683312"}`)}},
+			},
+		},
+	}
+}
+
+// StartGNMIDialout connects to the given address and serves the gNMI
+// mock server over the established connection (reverse gNMI).
+func StartGNMIDialout(addr string, resp Response) (net.Listener, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	ln := &connListener{conn: conn, done: make(chan struct{})}
+	gServer := grpc.NewServer()
+	mockServer := &GNMIServer{Resp: resp}
+	gnmi.RegisterGNMIServer(gServer, mockServer)
+
+	go func() {
+		gServer.Serve(ln)
+	}()
+
+	return ln, nil
+}
+
+// connListener represents a listener with an established connection.
+type connListener struct {
+	conn net.Conn
+	once sync.Once
+	done chan struct{}
+}
+
+// Accept returns the established connection once.
+func (l *connListener) Accept() (net.Conn, error) {
+	var conn net.Conn
+
+	l.once.Do(func() { conn = l.conn })
+	if conn != nil {
+		return conn, nil
+	}
+
+	<-l.done
+
+	return nil, errors.New("listener closed")
+}
+
+// Close closes the established connection.
+func (l *connListener) Close() error {
+	select {
+	case <-l.done:
+	default:
+		close(l.done)
+	}
+
+	return l.conn.Close()
+}
+
+// Addr returns the local address of the established connection.
+func (l *connListener) Addr() net.Addr {
+	return l.conn.LocalAddr()
+}
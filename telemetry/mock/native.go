@@ -0,0 +1,93 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package mock
+
+import (
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/runtime/protoimpl"
+
+	tpb "github.com/yahoo/panoptes-stream/telemetry/juniper/proto/telemetry_top"
+)
+
+// JuniperNativeInterface returns Junos native sensor mock data included an interface metrics
+func JuniperNativeInterface() *tpb.TelemetryStream {
+	return &tpb.TelemetryStream{
+		SystemId:       proto.String("core1.lax"),
+		ComponentId:    proto.Uint32(1),
+		SensorName:     proto.String("ifd:/junos/system/linecard/interface/:/junos/system/linecard/interface/:PFE"),
+		SequenceNumber: proto.Uint32(1),
+		Timestamp:      proto.Uint64(1596067993610),
+		Enterprise: enterprise(tpb.E_JnprInterfaceExt, &tpb.Port{
+			InterfaceStats: []*tpb.InterfaceInfos{
+				{
+					IfName:      proto.String("et-0/0/0"),
+					SnmpIfIndex: proto.Uint32(520),
+					IngressStats: &tpb.InterfaceStats{
+						IfPkts:   proto.Uint64(23609955),
+						IfOctets: proto.Uint64(52613105736),
+					},
+					EgressQueueInfo: []*tpb.QueueStats{
+						{QueueNumber: proto.Uint32(0), Packets: proto.Uint64(1024)},
+					},
+					IfOperationalStatus: proto.String("UP"),
+				},
+			},
+		}),
+	}
+}
+
+// JuniperNativeFirewall returns Junos native sensor mock data included a firewall filter metrics
+func JuniperNativeFirewall() *tpb.TelemetryStream {
+	return &tpb.TelemetryStream{
+		SystemId:   proto.String("core1.lax"),
+		SensorName: proto.String("fw:/junos/system/linecard/firewall/:/junos/system/linecard/firewall/:PFE"),
+		Timestamp:  proto.Uint64(1596067993610),
+		Enterprise: enterprise(tpb.E_JnprFirewallExt, &tpb.Firewall{
+			FirewallStats: []*tpb.FirewallStats{
+				{
+					FilterName: proto.String("protect-re"),
+					CounterStats: []*tpb.CounterStats{
+						{Name: proto.String("ssh"), Packets: proto.Uint64(10), Bytes: proto.Uint64(1200)},
+					},
+				},
+			},
+		}),
+	}
+}
+
+// JuniperNativeLSP returns Junos native sensor mock data included a LSP statistics
+func JuniperNativeLSP() *tpb.TelemetryStream {
+	return &tpb.TelemetryStream{
+		SystemId:   proto.String("core1.lax"),
+		SensorName: proto.String("lsp:/junos/services/label-switched-path/usage/:/junos/services/label-switched-path/usage/:PFE"),
+		Timestamp:  proto.Uint64(1596067993610),
+		Enterprise: enterprise(tpb.E_JnprLspStatisticsExt, &tpb.LspStats{
+			LspStatsRecords: []*tpb.LspStatsRecord{
+				{
+					Name:               proto.String("lax-sjc"),
+					InstanceIdentifier: proto.Uint32(0),
+					CounterName:        proto.String("c-1"),
+					Packets:            proto.Uint64(5000),
+					Bytes:              proto.Uint64(640000),
+				},
+			},
+		}),
+	}
+}
+
+// enterprise wraps the sensor message into the juniperNetworks extension
+// of the enterprise sensors.
+func enterprise(ext *protoimpl.ExtensionInfo, sensor proto.Message) *tpb.EnterpriseSensors {
+	jnpr := &tpb.JuniperNetworksSensors{}
+	if err := proto.SetExtension(jnpr, ext, sensor); err != nil {
+		panic(err)
+	}
+
+	e := &tpb.EnterpriseSensors{}
+	if err := proto.SetExtension(e, tpb.E_JuniperNetworks, jnpr); err != nil {
+		panic(err)
+	}
+
+	return e
+}
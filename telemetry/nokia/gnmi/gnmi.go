@@ -0,0 +1,96 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package gnmi
+
+import (
+	"strings"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+
+	generic "github.com/yahoo/panoptes-stream/telemetry/generic/gnmi"
+)
+
+var gnmiVersion = "0.7.0"
+
+// New creates a gNMI for Nokia SR OS and SR Linux telemetry, it's the
+// vendor-neutral gNMI with the Nokia paths and JSON_IETF values.
+var New = generic.NewVendor(generic.Options{
+	Name:          "nokia.gnmi",
+	Encoding:      gpb.Encoding_JSON_IETF,
+	NormalizePath: normalizePath,
+	GetValues:     getValues,
+})
+
+// normalizePath removes the YANG module names from the path elements
+// and their keys e.g. nokia-state:state/port[nokia-state:port-id=1/1/1]
+// and srl_nokia-interfaces:interface[name=ethernet-1/1]
+func normalizePath(path []*gpb.PathElem) []*gpb.PathElem {
+	var elems = make([]*gpb.PathElem, 0, len(path))
+
+	for _, elem := range path {
+		e := &gpb.PathElem{Name: trimModule(elem.Name)}
+
+		if len(elem.Key) > 0 {
+			e.Key = make(map[string]string, len(elem.Key))
+			for k, v := range elem.Key {
+				e.Key[trimModule(k)] = v
+			}
+		}
+
+		elems = append(elems, e)
+	}
+
+	return elems
+}
+
+func trimModule(name string) string {
+	if i := strings.Index(name, ":"); i > -1 {
+		return name[i+1:]
+	}
+
+	return name
+}
+
+// getValues returns the update value per leaf, the JSON_IETF container values
+// are flattened to the leaves in order to have the same shape as the other vendors.
+func getValues(key string, path []*gpb.PathElem, tv *gpb.TypedValue) (map[string]interface{}, error) {
+	var leaf string
+
+	if len(path) > 0 {
+		leaf = path[len(path)-1].Name
+	}
+
+	value, err := generic.GetJSONValue(tv, leaf)
+	if err != nil {
+		return nil, err
+	}
+
+	kv := make(map[string]interface{})
+	flatten(kv, key, value)
+
+	return kv, nil
+}
+
+// flatten converts a JSON container to key value
+// the keys of the JSON container are trimmed from module name.
+func flatten(kv map[string]interface{}, key string, value interface{}) {
+	m, ok := value.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		kv[key] = value
+		return
+	}
+
+	for k, v := range m {
+		if len(key) > 0 {
+			flatten(kv, key+"/"+trimModule(k), v)
+		} else {
+			flatten(kv, trimModule(k), v)
+		}
+	}
+}
+
+// Version returns the current package version.
+func Version() string {
+	return gnmiVersion
+}
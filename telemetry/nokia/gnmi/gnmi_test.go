@@ -0,0 +1,110 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package gnmi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/telemetry"
+	"github.com/yahoo/panoptes-stream/telemetry/mock"
+)
+
+func TestNokiaSROSPort(t *testing.T) {
+	var (
+		addr    = "127.0.0.1:50520"
+		ch      = make(telemetry.ExtDSChan, 10)
+		sensors []*config.Sensor
+	)
+	ln, err := mock.StartGNMIServer(addr, mock.Update{Notification: mock.NokiaSROSPort(), Attempt: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	cfg := config.NewMockConfig()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*500)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sensors = append(sensors, &config.Sensor{
+		Service: "nokia.gnmi",
+		Output:  "console::stdout",
+		Path:    "/state/port/statistics",
+	})
+
+	g := New(cfg.Logger(), conn, sensors, ch)
+	g.Start(ctx)
+
+	r := make(map[string]telemetry.ExtDataStore)
+	for i := 0; i < 3; i++ {
+		select {
+		case resp := <-ch:
+			r[resp.DS["key"].(string)] = resp
+		case <-ctx.Done():
+			t.Fatal("timeout")
+		}
+	}
+
+	resp := r["in-octets"]
+	assert.Equal(t, "/state/port/statistics", resp.DS["prefix"])
+	assert.Equal(t, "127.0.0.1", resp.DS["system_id"])
+	assert.Equal(t, int64(1602683312448512580), resp.DS["timestamp"])
+	assert.Equal(t, map[string]string{"port-id": "1/1/1"}, resp.DS["labels"])
+	assert.Equal(t, uint64(28342234521), resp.DS["value"])
+	assert.Equal(t, "console::stdout", resp.Output)
+
+	assert.Equal(t, uint64(1834236711), r["out-octets"].DS["value"])
+	assert.Equal(t, uint64(28342234521), r["in-octets-64"].DS["value"])
+}
+
+func TestGetValues(t *testing.T) {
+	n := mock.NokiaSRLInterface()
+	path := normalizePath(n.Update[0].Path.Elem)
+
+	kv, err := getValues("statistics", path, n.Update[0].Val)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"statistics/in-octets":           uint64(1043),
+		"statistics/out-octets":          uint64(2391),
+		"statistics/carrier-transitions": uint64(1),
+		"statistics/in-bytes":            uint64(1043),
+		"statistics/last-change":         uint64(1602683312),
+	}, kv)
+
+	// the strings of the other leaves aren't converted
+	kv, err = getValues("description", path, &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"0012"`)}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"description": "0012"}, kv)
+
+	_, err = getValues("", path, &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{`)}})
+	assert.Error(t, err)
+}
+
+func TestNormalizePath(t *testing.T) {
+	path := normalizePath([]*gnmi.PathElem{
+		{Name: "nokia-state:state"},
+		{Name: "router", Key: map[string]string{"nokia-state:router-name": "Base"}},
+		{Name: "interface", Key: map[string]string{"interface-name": "system"}},
+	})
+
+	assert.Equal(t, "state", path[0].Name)
+	assert.Equal(t, map[string]string{"router-name": "Base"}, path[1].Key)
+	assert.Equal(t, map[string]string{"interface-name": "system"}, path[2].Key)
+}
+
+func TestVersion(t *testing.T) {
+	assert.Equal(t, gnmiVersion, Version())
+}
@@ -0,0 +1,14 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package nokia
+
+import (
+	"github.com/yahoo/panoptes-stream/telemetry"
+	"github.com/yahoo/panoptes-stream/telemetry/nokia/gnmi"
+)
+
+// Register Nokia telemetries
+func Register(telemetryRegistrar *telemetry.Registrar) {
+	telemetryRegistrar.Register("nokia.gnmi", gnmi.Version(), gnmi.New)
+}
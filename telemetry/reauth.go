@@ -0,0 +1,150 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package telemetry
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/secret"
+)
+
+// session represents a device service connection and its NMI.
+type session struct {
+	ctx    context.Context
+	conn   *grpc.ClientConn
+	cancel context.CancelFunc
+	done   chan error
+}
+
+// reauthServices holds the device services channels which they're
+// notified once the secrets they depend on changed.
+type reauthServices struct {
+	sync.Mutex
+	services map[chan struct{}][]string
+}
+
+func newReauthServices() *reauthServices {
+	return &reauthServices{services: make(map[chan struct{}][]string)}
+}
+
+// add returns a channel which is notified once one of the secrets changed,
+// it returns nil if there is no secret (nil channel never notifies).
+func (r *reauthServices) add(keys []string) chan struct{} {
+	if len(keys) < 1 {
+		return nil
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	ch := make(chan struct{}, 1)
+	r.services[ch] = keys
+
+	// the secrets are kept cached to be notified once they changed
+	secret.Hold(keys...)
+
+	return ch
+}
+
+func (r *reauthServices) del(ch chan struct{}) {
+	if ch == nil {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	secret.Release(r.services[ch]...)
+	delete(r.services, ch)
+}
+
+// notify notifies the services which they depend on the secret and returns the number of them.
+func (r *reauthServices) notify(key string) int {
+	var n int
+
+	r.Lock()
+	defer r.Unlock()
+
+	for ch, keys := range r.services {
+		for _, k := range keys {
+			if k != key {
+				continue
+			}
+
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+
+			n++
+			break
+		}
+	}
+
+	return n
+}
+
+// reauthLoop re-authenticates the device services once their secrets changed.
+func (t *Telemetry) reauthLoop(ch chan string, unsubscribe func()) {
+	defer unsubscribe()
+
+	for {
+		select {
+		case key := <-ch:
+			// the dialers are rebuilt with the new credentials
+			t.dialers.purge(nil)
+			n := t.reauth.notify(key)
+			t.logger.Info("secret", zap.String("event", "changed"), zap.String("key", key), zap.Int("services", n))
+		case <-t.ctx.Done():
+			return
+		}
+	}
+}
+
+// getDeviceSecrets returns the remote secrets keys which the device credentials,
+// TLS certificate and dialer depend on.
+func (t *Telemetry) getDeviceSecrets(device *config.Device) []string {
+	var (
+		keys    []string
+		refs    []string
+		gCfg    = t.cfg.Global()
+		dialer  = device.Dialer
+		options = gCfg.DeviceOptions
+	)
+
+	if device.Username != "" {
+		refs = append(refs, device.Username)
+	} else {
+		refs = append(refs, options.Username)
+	}
+
+	if device.TLSConfig.Enabled {
+		if device.TLSConfig.CertFile != "" {
+			refs = append(refs, device.TLSConfig.CertFile)
+		} else if options.TLSConfig.Enabled {
+			refs = append(refs, options.TLSConfig.CertFile)
+		}
+	}
+
+	if dialer == "" {
+		dialer = options.Dialer
+	}
+
+	if d, ok := gCfg.Dialers[dialer]; ok {
+		refs = append(refs, d.Username, d.KeyFile)
+	}
+
+	for _, ref := range refs {
+		if sType, path, ok := secret.ParseRemoteSecretInfo(ref); ok {
+			keys = append(keys, secret.SecretKey(sType, path))
+		}
+	}
+
+	return keys
+}
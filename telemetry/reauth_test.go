@@ -0,0 +1,111 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/telemetry/mock"
+)
+
+func TestReauthServices(t *testing.T) {
+	r := newReauthServices()
+
+	assert.Nil(t, r.add(nil))
+
+	ch1 := r.add([]string{"__vault::secrets/core", "__vault::secrets/tls"})
+	ch2 := r.add([]string{"__vault::secrets/edge"})
+
+	assert.Equal(t, 1, r.notify("__vault::secrets/tls"))
+	assert.Len(t, ch1, 1)
+	assert.Len(t, ch2, 0)
+
+	// pending notification isn't blocked
+	assert.Equal(t, 1, r.notify("__vault::secrets/core"))
+	assert.Len(t, ch1, 1)
+
+	r.del(ch1)
+	r.del(nil)
+	assert.Equal(t, 0, r.notify("__vault::secrets/core"))
+}
+
+func TestGetDeviceSecrets(t *testing.T) {
+	cfg := &config.MockConfig{MGlobal: &config.Global{}}
+	tm := &Telemetry{cfg: cfg, logger: zap.NewNop()}
+
+	cfg.MGlobal.DeviceOptions = config.DeviceOptions{
+		Username:  "__vault::secrets/creds",
+		TLSConfig: config.TLSConfig{Enabled: true, CertFile: "__vault::secrets/tls"},
+		Dialer:    "bastion",
+	}
+	cfg.MGlobal.Dialers = map[string]config.Dialer{
+		"bastion": {Type: "ssh", Addr: "127.0.0.1:22", Username: "panoptes", KeyFile: "__vault::secrets/ssh"},
+	}
+
+	device := &config.Device{}
+	assert.Equal(t, []string{"__vault::secrets/creds", "__vault::secrets/ssh"}, tm.getDeviceSecrets(device))
+
+	device.Username = "admin"
+	device.TLSConfig.Enabled = true
+	assert.Equal(t, []string{"__vault::secrets/tls", "__vault::secrets/ssh"}, tm.getDeviceSecrets(device))
+
+	cfg.MGlobal = &config.Global{}
+	assert.Len(t, tm.getDeviceSecrets(device), 0)
+}
+
+func TestSessionReplace(t *testing.T) {
+	addr := "127.0.0.1:50066"
+
+	ln, err := mock.StartGNMICapServer(addr, mock.Update{}, capabilityResponse())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	cfg := &config.MockConfig{MGlobal: &config.Global{}}
+	tr := NewRegistrar(zap.NewNop())
+	tr.Register("test.gnmi", "0.0.0", testGnmiNew)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tm := New(ctx, cfg, tr, make(ExtDSChan, 1))
+	tm.logger = zap.NewNop()
+	device := &config.Device{}
+	device.Timeout = 2
+
+	s1, err := tm.connect(ctx, device, "test.gnmi", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm.startSession(s1, "test.gnmi", nil, nil)
+
+	// new session is up before closing the current one
+	s2, err := tm.connect(ctx, device, "test.gnmi", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm.startSession(s2, "test.gnmi", nil, nil)
+	assert.Equal(t, uint64(2), tm.metrics["gRPConnCurrent"].Get())
+
+	tm.closeSession(s1)
+
+	select {
+	case err := <-s1.done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("expect to terminate the replaced session")
+	}
+
+	assert.Len(t, s2.done, 0)
+
+	tm.closeSession(s2)
+	assert.Equal(t, uint64(0), tm.metrics["gRPConnCurrent"].Get())
+}
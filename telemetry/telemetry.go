@@ -7,10 +7,11 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
-	"math/rand"
+	"fmt"
 	"net"
 	"reflect"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -37,6 +38,10 @@ type Telemetry struct {
 	telemetryRegistrar *Registrar
 	informer           chan struct{}
 	deviceFilterOpts   DeviceFilterOpts
+	dialLimiter        dialLimiter
+	dialLimiterOnce    sync.Once
+	dialers            dialerCache
+	reauth             *reauthServices
 	metrics            map[string]status.Metrics
 }
 
@@ -55,45 +60,28 @@ type DeviceFilterOpts struct {
 // DeviceFilterOpt represents filter option
 type DeviceFilterOpt func(config.Device) bool
 
-type mdtCredentials struct {
-	username string
-	password string
+// dialerCache holds the dialers per name along with their config.
+type dialerCache struct {
+	sync.Mutex
+	dialers map[string]cachedDialer
 }
 
-type backoff struct {
-	d    time.Duration
-	last time.Time
+type cachedDialer struct {
+	cfg    config.Dialer
+	dialer ContextDialer
 }
 
-func (b *backoff) reset() {
-	rand.Seed(time.Now().UnixNano())
-
-	b.last = time.Now()
-	b.d = time.Duration(20 + rand.Intn(30))
+// dialLimiter holds the dial slots along with the configured size,
+// it's rebuilt once the size changes.
+type dialLimiter struct {
+	sync.Mutex
+	size  int
+	slots chan struct{}
 }
 
-func (b *backoff) next() time.Duration {
-	// first call - bypass backoff
-	if b.d == 0 {
-		b.reset()
-		return 0
-	}
-
-	// reset back off
-	if time.Since(b.last).Seconds() > float64(30*60) {
-		b.reset()
-		return b.d
-	}
-
-	// limit backoff
-	if time.Since(b.last).Seconds() < float64(5*60) {
-		if b.d < time.Duration(5*60) {
-			b.d += b.d * 50 / 100
-			b.last = time.Now()
-		}
-	}
-
-	return b.d
+type mdtCredentials struct {
+	username string
+	password string
 }
 
 // New creates a new telemetry
@@ -103,10 +91,12 @@ func New(ctx context.Context, cfg config.Config, tr *Registrar, outChan ExtDSCha
 	metrics["devicesCurrent"] = status.NewGauge("subscribed_devices", "")
 	metrics["gRPConnCurrent"] = status.NewGauge("active_grpc_connections", "")
 	metrics["reconnectsTotal"] = status.NewCounter("grpc_reconnects_total", "")
+	metrics["dialsPending"] = status.NewGauge("grpc_dials_pending", "")
+	metrics["reauthTotal"] = status.NewCounter("grpc_reauth_total", "")
 
 	status.Register(nil, metrics)
 
-	return &Telemetry{
+	t := &Telemetry{
 		ctx:                ctx,
 		cfg:                cfg,
 		logger:             cfg.Logger(),
@@ -116,8 +106,16 @@ func New(ctx context.Context, cfg config.Config, tr *Registrar, outChan ExtDSCha
 		informer:           make(chan struct{}, 1),
 		outChan:            outChan,
 		telemetryRegistrar: tr,
+		reauth:             newReauthServices(),
 		metrics:            metrics,
 	}
+
+	// rotated secrets re-authenticate the devices which they depend on
+	secretChanges := make(chan string, 100)
+	unsubscribe := secret.Notify(secretChanges)
+	go t.reauthLoop(secretChanges, unsubscribe)
+
+	return t
 }
 
 func (t *Telemetry) subscribe(device config.Device) {
@@ -138,15 +136,28 @@ func (t *Telemetry) subscribe(device config.Device) {
 	}
 
 	for service, sensors := range sensorsPerService {
+		// dial-out sensors are served by the dial-out services
+		if strings.HasSuffix(service, ".dialout") {
+			continue
+		}
+
 		go func(service string, sensors []*config.Sensor) {
 			addr := net.JoinHostPort(device.Host, strconv.Itoa(device.Port))
-			backoff := backoff{}
+			backoff := newBackoff(t.getBackoffPolicy(&device))
+
+			wd := newWatchdog(t.cfg, device.Host, service, sensors)
+			if wd != nil {
+				defer wd.stop()
+			}
+
+			reauth := t.reauth.add(t.getDeviceSecrets(&device))
+			defer t.reauth.del(reauth)
 
 			for {
 				backoffDuration := backoff.next()
 
 				select {
-				case <-time.After(time.Second * backoffDuration):
+				case <-time.After(backoffDuration):
 					if backoffDuration != 0 {
 						t.metrics["reconnectsTotal"].Inc()
 					}
@@ -155,51 +166,118 @@ func (t *Telemetry) subscribe(device config.Device) {
 					return
 				}
 
-				ctx, err := t.setCredentials(ctx, &device)
+				s, err := t.connect(ctx, &device, service, addr)
 				if err != nil {
-					t.logger.Error("subscribe", zap.String("event", "grpc.credentials"), zap.Error(err))
+					if ctx.Err() != nil {
+						return
+					}
 					continue
 				}
 
-				opts, err := t.getDialOpts(&device, service)
-				if err != nil {
-					t.logger.Error("subscribe", zap.String("event", "grpc.dialopts"), zap.Error(err))
-					continue
+				t.startSession(s, service, sensors, wd)
+
+				// the device secrets changed: the new session is established
+				// before closing the current one to avoid a data gap.
+				for s != nil {
+					select {
+					case err = <-s.done:
+						t.closeSession(s)
+						s = nil
+
+						if err != nil {
+							t.logger.Warn("subscribe", zap.String("event", "nmi"), zap.Error(err), zap.String("host", device.Host), zap.String("service", service))
+						} else {
+							t.logger.Warn("subscribe", zap.String("event", "grpc.terminate"), zap.String("host", device.Host), zap.String("service", service))
+						}
+
+					case <-reauth:
+						ns, err := t.connect(ctx, &device, service, addr)
+						if err != nil {
+							continue
+						}
+
+						t.startSession(ns, service, sensors, wd)
+						t.closeSession(s)
+						s = ns
+
+						t.metrics["reauthTotal"].Inc()
+						t.logger.Info("subscribe", zap.String("event", "reauth"), zap.String("host", device.Host), zap.String("service", service))
+					}
 				}
+			}
+		}(service, sensors)
+	}
+}
 
-				gCtx, cancel := context.WithTimeout(ctx, t.getTimeout(device.Timeout))
-				conn, err := grpc.DialContext(gCtx, addr, opts...)
-				cancel()
-				if err != nil {
-					t.logger.Error("subscribe", zap.String("event", "grpc.dial"), zap.String("host", device.Host), zap.Error(err))
-					continue
-				}
+// connect dials the device with the current credentials and returns a new session.
+func (t *Telemetry) connect(ctx context.Context, device *config.Device, service, addr string) (*session, error) {
+	ctx, err := t.setCredentials(ctx, device)
+	if err != nil {
+		t.logger.Error("subscribe", zap.String("event", "grpc.credentials"), zap.Error(err))
+		return nil, err
+	}
 
-				t.metrics["gRPConnCurrent"].Inc()
-				t.logger.Info("subscribe", zap.String("event", "grpc.connect"), zap.String("host", device.Host), zap.String("service", service))
+	opts, err := t.getDialOpts(device, service)
+	if err != nil {
+		t.logger.Error("subscribe", zap.String("event", "grpc.dialopts"), zap.Error(err))
+		return nil, err
+	}
 
-				new, _ := t.telemetryRegistrar.GetNMIFactory(service)
-				nmi := new(t.logger, conn, sensors, t.outChan)
-				err = nmi.Start(ctx)
+	slots, ok := t.acquireDial(ctx)
+	if !ok {
+		return nil, ctx.Err()
+	}
 
-				conn.Close()
-				t.metrics["gRPConnCurrent"].Dec()
+	gCtx, cancel := context.WithTimeout(ctx, t.getTimeout(device.Timeout))
+	conn, err := grpc.DialContext(gCtx, addr, opts...)
+	cancel()
+	t.releaseDial(slots)
+	if err != nil {
+		t.logger.Error("subscribe", zap.String("event", "grpc.dial"), zap.String("host", device.Host), zap.Error(err))
+		return nil, err
+	}
 
-				if err != nil {
-					t.logger.Warn("subscribe", zap.String("event", "nmi"), zap.Error(err), zap.String("host", device.Host), zap.String("service", service))
-				} else {
-					t.logger.Warn("subscribe", zap.String("event", "grpc.terminate"), zap.String("host", device.Host), zap.String("service", service))
-				}
+	t.metrics["gRPConnCurrent"].Inc()
+	t.logger.Info("subscribe", zap.String("event", "grpc.connect"), zap.String("host", device.Host), zap.String("service", service))
 
-			}
-		}(service, sensors)
+	return &session{ctx: ctx, conn: conn}, nil
+}
+
+// startSession starts the service NMI on the session connection,
+// the NMI result is available at the session done channel.
+func (t *Telemetry) startSession(s *session, service string, sensors []*config.Sensor, wd *watchdog) {
+	var nmiCtx context.Context
+
+	nmiCtx, s.cancel = context.WithCancel(s.ctx)
+	s.done = make(chan error, 1)
+
+	// the watchdog cancels the nmi context once a sensor is stale
+	outChan := t.outChan
+	if wd != nil {
+		outChan = wd.run(nmiCtx, s.cancel, t.outChan)
 	}
+
+	new, _ := t.telemetryRegistrar.GetNMIFactory(service)
+	nmi := new(t.logger, s.conn, sensors, outChan)
+
+	go func() {
+		s.done <- nmi.Start(nmiCtx)
+	}()
+}
+
+func (t *Telemetry) closeSession(s *session) {
+	s.cancel()
+	s.conn.Close()
+	t.metrics["gRPConnCurrent"].Dec()
 }
 
 func (t *Telemetry) unsubscribe(device config.Device) {
 	t.register[device.Host]()
 	delete(t.register, device.Host)
 	delete(t.devices, device.Host)
+	for service := range device.Sensors {
+		status.DeleteCapabilities(device.Host, service)
+	}
 	t.metrics["devicesCurrent"].Dec()
 }
 
@@ -217,6 +295,9 @@ func (t *Telemetry) Update() {
 		return
 	}
 
+	t.dialers.purge(t.cfg.Global().Dialers)
+	t.dialLimiter.update(t.cfg.Global().MaxConcurrentDials)
+
 	newDevices := make(map[string]config.Device)
 	delta := new(delta)
 
@@ -325,6 +406,18 @@ func (t *Telemetry) getDialOpts(device *config.Device, service string) ([]grpc.D
 		opts = append(opts, grpc.WithInsecure())
 	}
 
+	transportOpts := GetTransportOptions(device.DeviceOptions, t.cfg.Global().DeviceOptions)
+	opts = append(opts, GetTransportDialOpts(transportOpts)...)
+
+	dialer, err := t.getDialer(device)
+	if err != nil {
+		return opts, err
+	}
+
+	if dialer != nil {
+		opts = append(opts, grpc.WithContextDialer(dialer))
+	}
+
 	if service == "cisco.mdt" {
 		creds := mdtCredentials{username: device.Username, password: device.Password}
 		opts = append(opts, grpc.WithPerRPCCredentials(creds))
@@ -333,6 +426,83 @@ func (t *Telemetry) getDialOpts(device *config.Device, service string) ([]grpc.D
 	return opts, nil
 }
 
+// getDialer returns the device dialer (proxy or jump host) or nil if the device is dialed directly,
+// the devices are bound to a dialer by name at device or global device options.
+func (t *Telemetry) getDialer(device *config.Device) (ContextDialer, error) {
+	gCfg := t.cfg.Global()
+
+	name := device.Dialer
+	if name == "" {
+		name = gCfg.DeviceOptions.Dialer
+	}
+
+	if name == "" {
+		return nil, nil
+	}
+
+	dCfg, ok := gCfg.Dialers[name]
+	if !ok {
+		return nil, fmt.Errorf("dialer %s not found", name)
+	}
+
+	if dialer, ok := t.dialers.get(name, dCfg); ok {
+		return dialer, nil
+	}
+
+	dialer, err, _ := t.group.Do("dialer::"+name, func() (interface{}, error) {
+		dialer, err := NewDialer(dCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		t.dialers.set(name, dCfg, dialer)
+
+		return dialer, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dialer.(ContextDialer), nil
+}
+
+// get returns the cached dialer once its config hasn't been changed.
+func (d *dialerCache) get(name string, cfg config.Dialer) (ContextDialer, bool) {
+	d.Lock()
+	defer d.Unlock()
+
+	c, ok := d.dialers[name]
+	if !ok || c.cfg != cfg {
+		return nil, false
+	}
+
+	return c.dialer, true
+}
+
+func (d *dialerCache) set(name string, cfg config.Dialer, dialer ContextDialer) {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.dialers == nil {
+		d.dialers = make(map[string]cachedDialer)
+	}
+
+	d.dialers[name] = cachedDialer{cfg: cfg, dialer: dialer}
+}
+
+// purge removes the dialers which they have been changed or removed
+// at the given dialers config, it removes all dialers once it's nil.
+func (d *dialerCache) purge(dialers map[string]config.Dialer) {
+	d.Lock()
+	defer d.Unlock()
+
+	for name, c := range d.dialers {
+		if cfg, ok := dialers[name]; !ok || c.cfg != cfg {
+			delete(d.dialers, name)
+		}
+	}
+}
+
 func (t *Telemetry) getTransportCredentials(device *config.Device) (credentials.TransportCredentials, error) {
 	var (
 		tlsConfig *config.TLSConfig
@@ -409,6 +579,88 @@ func (t *Telemetry) getTimeout(timeout int) time.Duration {
 	return time.Second * 5
 }
 
+// getBackoffPolicy returns the device backoff policy, the options
+// which they're not configured at device are taken from global.
+func (t *Telemetry) getBackoffPolicy(device *config.Device) config.Backoff {
+	var (
+		policy  = device.Backoff
+		gPolicy = t.cfg.Global().DeviceOptions.Backoff
+	)
+
+	if policy.Initial == 0 {
+		policy.Initial = gPolicy.Initial
+	}
+	if policy.Max == 0 {
+		policy.Max = gPolicy.Max
+	}
+	if policy.Multiplier == 0 {
+		policy.Multiplier = gPolicy.Multiplier
+	}
+	if policy.Jitter == nil {
+		policy.Jitter = gPolicy.Jitter
+	}
+	if policy.ResetWindow == 0 {
+		policy.ResetWindow = gPolicy.ResetWindow
+	}
+
+	return policy
+}
+
+// update rebuilds the dial slots if the size changed, the in-flight
+// dials release their slots to the previous slots.
+func (d *dialLimiter) update(size int) {
+	d.Lock()
+	defer d.Unlock()
+
+	if size == d.size {
+		return
+	}
+
+	d.size = size
+	d.slots = nil
+
+	if size > 0 {
+		d.slots = make(chan struct{}, size)
+	}
+}
+
+func (d *dialLimiter) get() chan struct{} {
+	d.Lock()
+	defer d.Unlock()
+
+	return d.slots
+}
+
+// acquireDial waits for a dial slot if the concurrent dials are limited, it returns
+// the slots to release to and false if the context canceled while waiting.
+func (t *Telemetry) acquireDial(ctx context.Context) (chan struct{}, bool) {
+	// limit the concurrent dials to avoid reconnect storms
+	t.dialLimiterOnce.Do(func() {
+		t.dialLimiter.update(t.cfg.Global().MaxConcurrentDials)
+	})
+
+	slots := t.dialLimiter.get()
+	if slots == nil {
+		return nil, true
+	}
+
+	t.metrics["dialsPending"].Inc()
+	defer t.metrics["dialsPending"].Dec()
+
+	select {
+	case slots <- struct{}{}:
+		return slots, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+func (t *Telemetry) releaseDial(slots chan struct{}) {
+	if slots != nil {
+		<-slots
+	}
+}
+
 func (m mdtCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
 	return map[string]string{
 		"username": m.username,
@@ -187,3 +187,70 @@ func TestGetTimeout(t *testing.T) {
 	to = tm.getTimeout(0)
 	assert.Equal(t, 4*time.Second, to)
 }
+
+func TestGetBackoffPolicy(t *testing.T) {
+	cfg := config.NewMockConfig()
+	tm := &Telemetry{cfg: cfg}
+
+	jitter, noJitter := 0.2, 0.0
+
+	cfg.MGlobal.DeviceOptions.Backoff = config.Backoff{Initial: 10, Max: 120, Multiplier: 2, Jitter: &jitter}
+
+	device := &config.Device{}
+	device.Backoff = config.Backoff{Initial: 5}
+
+	assert.Equal(t, config.Backoff{Initial: 5, Max: 120, Multiplier: 2, Jitter: &jitter}, tm.getBackoffPolicy(device))
+
+	// the device overrides the global jitter to zero
+	device.Backoff.Jitter = &noJitter
+	assert.Equal(t, 0.0, *tm.getBackoffPolicy(device).Jitter)
+}
+
+func TestDialLimiter(t *testing.T) {
+	cfg := config.NewMockConfig()
+	cfg.MGlobal.MaxConcurrentDials = 1
+
+	tm := New(context.Background(), cfg, NewRegistrar(cfg.Logger()), nil)
+
+	slots, ok := tm.acquireDial(context.Background())
+	assert.True(t, ok)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// the only slot is taken
+	_, ok = tm.acquireDial(ctx)
+	assert.False(t, ok)
+
+	tm.releaseDial(slots)
+	slots, ok = tm.acquireDial(context.Background())
+	assert.True(t, ok)
+
+	// the size changed, the in-flight dial releases to the previous slots
+	cfg.MGlobal.MaxConcurrentDials = 2
+	tm.Update()
+
+	newSlots, ok := tm.acquireDial(context.Background())
+	assert.True(t, ok)
+	_, ok = tm.acquireDial(context.Background())
+	assert.True(t, ok)
+	assert.Equal(t, 2, cap(newSlots))
+
+	tm.releaseDial(slots)
+	assert.Len(t, slots, 0)
+	assert.Len(t, newSlots, 2)
+
+	// not limited anymore
+	cfg.MGlobal.MaxConcurrentDials = 0
+	tm.Update()
+
+	slots, ok = tm.acquireDial(context.Background())
+	assert.True(t, ok)
+	assert.Nil(t, slots)
+
+	// not limited
+	tm = &Telemetry{cfg: config.NewMockConfig()}
+	slots, ok = tm.acquireDial(context.Background())
+	assert.True(t, ok)
+	tm.releaseDial(slots)
+}
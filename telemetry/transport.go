@@ -0,0 +1,110 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package telemetry
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/keepalive"
+
+	"github.com/yahoo/panoptes-stream/config"
+)
+
+// GetTransportOptions returns the device gRPC transport options, the options
+// which they're not configured at device are taken from the global device options.
+func GetTransportOptions(device, global config.DeviceOptions) config.DeviceOptions {
+	if device.KeepaliveTime == 0 {
+		device.KeepaliveTime = global.KeepaliveTime
+	}
+	if device.KeepaliveTimeout == 0 {
+		device.KeepaliveTimeout = global.KeepaliveTimeout
+	}
+	if device.MaxRecvMsgSize == 0 {
+		device.MaxRecvMsgSize = global.MaxRecvMsgSize
+	}
+	if device.InitialWindowSize == 0 {
+		device.InitialWindowSize = global.InitialWindowSize
+	}
+	if !device.Gzip {
+		device.Gzip = global.Gzip
+	}
+
+	return device
+}
+
+// GetTransportDialOpts returns the gRPC dial options of the transport options.
+func GetTransportDialOpts(opts config.DeviceOptions) []grpc.DialOption {
+	var (
+		dialOpts []grpc.DialOption
+		callOpts []grpc.CallOption
+	)
+
+	if opts.KeepaliveTime > 0 {
+		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                time.Duration(opts.KeepaliveTime) * time.Second,
+			Timeout:             getKeepaliveTimeout(opts),
+			PermitWithoutStream: true,
+		}))
+	}
+
+	if opts.InitialWindowSize > 0 {
+		dialOpts = append(dialOpts, grpc.WithInitialWindowSize(opts.InitialWindowSize))
+		dialOpts = append(dialOpts, grpc.WithInitialConnWindowSize(opts.InitialWindowSize))
+	}
+
+	if opts.MaxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(opts.MaxRecvMsgSize))
+	}
+
+	// gzip compresses the requests and advertises it as the accepted encoding, the
+	// client can't request compressed responses and the device decides to compress.
+	if opts.Gzip {
+		callOpts = append(callOpts, grpc.UseCompressor(gzip.Name))
+	}
+
+	if len(callOpts) > 0 {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+
+	return dialOpts
+}
+
+// GetTransportServerOpts returns the gRPC server options of the transport options,
+// the gzip compressed requests are accepted regardless and replied compressed.
+func GetTransportServerOpts(opts config.DeviceOptions) []grpc.ServerOption {
+	var srvOpts []grpc.ServerOption
+
+	if opts.KeepaliveTime > 0 {
+		srvOpts = append(srvOpts, grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    time.Duration(opts.KeepaliveTime) * time.Second,
+			Timeout: getKeepaliveTimeout(opts),
+		}))
+		// the devices are allowed to ping as frequent as the server
+		srvOpts = append(srvOpts, grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             time.Duration(opts.KeepaliveTime) * time.Second,
+			PermitWithoutStream: true,
+		}))
+	}
+
+	if opts.InitialWindowSize > 0 {
+		srvOpts = append(srvOpts, grpc.InitialWindowSize(opts.InitialWindowSize))
+		srvOpts = append(srvOpts, grpc.InitialConnWindowSize(opts.InitialWindowSize))
+	}
+
+	if opts.MaxRecvMsgSize > 0 {
+		srvOpts = append(srvOpts, grpc.MaxRecvMsgSize(opts.MaxRecvMsgSize))
+	}
+
+	return srvOpts
+}
+
+func getKeepaliveTimeout(opts config.DeviceOptions) time.Duration {
+	if opts.KeepaliveTimeout > 0 {
+		return time.Duration(opts.KeepaliveTimeout) * time.Second
+	}
+
+	return 20 * time.Second
+}
@@ -0,0 +1,64 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/telemetry/mock"
+)
+
+func TestGetTransportOptions(t *testing.T) {
+	global := config.DeviceOptions{KeepaliveTime: 30, MaxRecvMsgSize: 16 << 20, Gzip: true}
+	device := config.DeviceOptions{KeepaliveTime: 10, InitialWindowSize: 1 << 20}
+
+	opts := GetTransportOptions(device, global)
+	assert.Equal(t, 10, opts.KeepaliveTime)
+	assert.Equal(t, 16<<20, opts.MaxRecvMsgSize)
+	assert.Equal(t, int32(1<<20), opts.InitialWindowSize)
+	assert.True(t, opts.Gzip)
+
+	assert.Len(t, GetTransportDialOpts(config.DeviceOptions{}), 0)
+	assert.Len(t, GetTransportDialOpts(opts), 4)
+	assert.Len(t, GetTransportServerOpts(opts), 5)
+	assert.Equal(t, 20*time.Second, getKeepaliveTimeout(opts))
+}
+
+func TestTransportDialOpts(t *testing.T) {
+	addr := "127.0.0.1:50061"
+
+	ln, err := mock.StartGNMICapServer(addr, mock.Update{}, capabilityResponse())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	opts := []grpc.DialOption{grpc.WithInsecure(), grpc.WithBlock()}
+	opts = append(opts, GetTransportDialOpts(config.DeviceOptions{
+		KeepaliveTime:     10,
+		MaxRecvMsgSize:    8 << 20,
+		InitialWindowSize: 1 << 20,
+		Gzip:              true,
+	})...)
+
+	conn, err := grpc.DialContext(ctx, addr, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	resp, err := gpb.NewGNMIClient(conn).Capabilities(ctx, &gpb.CapabilityRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, "0.7.0", resp.GNMIVersion)
+}
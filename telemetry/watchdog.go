@@ -0,0 +1,266 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package telemetry
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yahoo/panoptes-stream/config"
+	"github.com/yahoo/panoptes-stream/status"
+)
+
+// watchdogBufferSize is the buffer size between a NMI and the watchdog.
+const watchdogBufferSize = 1000
+
+var regxPathKeys = regexp.MustCompile(`\[[^\]]*\]`)
+
+// watchdog tracks the last update time per sensor of a device service
+// since a stream can stay open while the device stops sending a sensor.
+type watchdog struct {
+	host            string
+	service         string
+	missedIntervals int
+	interval        time.Duration
+	sensors         []*watchedSensor
+	single          bool
+	logger          *zap.Logger
+	metrics         map[string]status.Metrics
+}
+
+type watchedSensor struct {
+	path         string
+	subscription string
+	interval     time.Duration
+	last         int64
+	stale        int32
+	labels       status.Labels
+	metrics      map[string]status.Metrics
+}
+
+// newWatchdog returns a watchdog of the sensors which they have an expected interval,
+// it returns nil if the watchdog is disabled or there is no sensor to watch.
+func newWatchdog(cfg config.Config, host, service string, sensors []*config.Sensor) *watchdog {
+	if !cfg.Global().Watchdog.Enabled {
+		return nil
+	}
+
+	w := &watchdog{
+		host:            host,
+		service:         service,
+		missedIntervals: cfg.Global().Watchdog.MissedIntervals,
+		single:          len(sensors) == 1,
+		logger:          cfg.Logger(),
+		metrics:         make(map[string]status.Metrics),
+	}
+
+	for _, sensor := range sensors {
+		interval := getExpectedInterval(sensor)
+		if interval <= 0 {
+			continue
+		}
+
+		name := sensor.Path
+		if name == "" {
+			name = sensor.Subscription
+		}
+
+		ws := &watchedSensor{
+			path:         normalizeSensorPath(sensor.Path),
+			subscription: sensor.Subscription,
+			interval:     interval,
+			labels:       status.Labels{"host": host, "service": service, "sensor": name},
+			metrics:      make(map[string]status.Metrics),
+		}
+
+		ws.metrics["lastUpdateSeconds"] = status.NewGauge("watchdog_last_update_seconds", "")
+		ws.metrics["missedIntervals"] = status.NewGauge("watchdog_missed_intervals", "")
+		ws.metrics["staleTotal"] = status.NewCounter("watchdog_stale_total", "")
+
+		if w.interval == 0 || interval < w.interval {
+			w.interval = interval
+		}
+
+		w.sensors = append(w.sensors, ws)
+	}
+
+	if len(w.sensors) < 1 {
+		return nil
+	}
+
+	w.metrics["resubscribesTotal"] = status.NewCounter("watchdog_resubscribes_total", "")
+	w.metrics["dropsTotal"] = status.NewCounter("watchdog_drops_total", "")
+
+	status.Register(status.Labels{"host": host, "service": service}, w.metrics)
+	for _, ws := range w.sensors {
+		status.Register(ws.labels, ws.metrics)
+	}
+
+	return w
+}
+
+// getExpectedInterval returns the interval which the sensor must be updated at least once,
+// the on_change sensors without heartbeat interval can be silent and they're not watched.
+func getExpectedInterval(sensor *config.Sensor) time.Duration {
+	switch strings.ToLower(sensor.ListMode) {
+	case "poll", "once":
+		if sensor.PollInterval > 0 {
+			return time.Duration(sensor.PollInterval) * time.Second
+		}
+		return defaultPollInterval * time.Second
+	}
+
+	if strings.ToLower(sensor.Mode) == "on_change" {
+		return time.Duration(sensor.HeartbeatInterval) * time.Second
+	}
+
+	if sensor.SampleInterval > 0 {
+		return time.Duration(sensor.SampleInterval) * time.Second
+	}
+
+	return time.Duration(sensor.HeartbeatInterval) * time.Second
+}
+
+// run forwards the NMI data to the output channel and checks the sensors per the
+// shortest sensor interval till the context canceled. it cancels the NMI once a
+// sensor missed the configured number of intervals (resubscription). the data is
+// dropped once the output channel is full as the NMIs do.
+func (w *watchdog) run(ctx context.Context, cancel context.CancelFunc, outChan ExtDSChan) ExtDSChan {
+	ch := make(ExtDSChan, watchdogBufferSize)
+	now := time.Now().UnixNano()
+
+	for _, ws := range w.sensors {
+		atomic.StoreInt64(&ws.last, now)
+	}
+
+	go func() {
+		for {
+			select {
+			case d := <-ch:
+				w.touch(d)
+
+				select {
+				case outChan <- d:
+				default:
+					w.metrics["dropsTotal"].Inc()
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if w.check(time.Now()) {
+					w.metrics["resubscribesTotal"].Inc()
+					w.logger.Warn("watchdog", zap.String("event", "resubscribe"), zap.String("host", w.host), zap.String("service", w.service))
+					cancel()
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// touch updates the last update time of the sensors which the data belongs to,
+// all data belongs to the sensor once the service has only one sensor.
+func (w *watchdog) touch(d ExtDataStore) {
+	var (
+		now       = time.Now().UnixNano()
+		prefix, _ = d.DS["prefix"].(string)
+		labels, _ = d.DS["labels"].(map[string]string)
+	)
+
+	prefix = normalizeSensorPath(prefix)
+
+	for _, ws := range w.sensors {
+		if w.single || ws.match(prefix, labels) {
+			atomic.StoreInt64(&ws.last, now)
+		}
+	}
+}
+
+// check updates the staleness metrics and returns true
+// if a sensor missed the configured number of intervals.
+func (w *watchdog) check(now time.Time) bool {
+	var resubscribe bool
+
+	for _, ws := range w.sensors {
+		since := now.Sub(time.Unix(0, atomic.LoadInt64(&ws.last)))
+		missed := int(since / ws.interval)
+
+		ws.metrics["lastUpdateSeconds"].Set(uint64(since.Seconds()))
+		ws.metrics["missedIntervals"].Set(uint64(missed))
+
+		if missed > 0 && atomic.CompareAndSwapInt32(&ws.stale, 0, 1) {
+			ws.metrics["staleTotal"].Inc()
+			w.logger.Warn("watchdog", zap.String("event", "stale"), zap.String("host", w.host),
+				zap.String("service", w.service), zap.String("sensor", ws.labels["sensor"]))
+		} else if missed == 0 {
+			atomic.StoreInt32(&ws.stale, 0)
+		}
+
+		if w.missedIntervals > 0 && missed >= w.missedIntervals {
+			resubscribe = true
+		}
+	}
+
+	return resubscribe
+}
+
+// stop unregisters the watchdog metrics.
+func (w *watchdog) stop() {
+	status.Unregister(status.Labels{"host": w.host, "service": w.service}, w.metrics)
+	for _, ws := range w.sensors {
+		status.Unregister(ws.labels, ws.metrics)
+	}
+}
+
+// match returns true if the data prefix and the sensor path are overlapped
+// or the data belongs to the sensor subscription (Cisco MDT).
+func (ws *watchedSensor) match(prefix string, labels map[string]string) bool {
+	if ws.subscription != "" && labels["subscriptionId"] == ws.subscription {
+		return true
+	}
+
+	if ws.path == "" || prefix == "" {
+		return false
+	}
+
+	return hasPathPrefix(prefix, ws.path) || hasPathPrefix(ws.path, prefix)
+}
+
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// normalizeSensorPath removes the keys, the module names and the leading and trailing slashes.
+func normalizeSensorPath(path string) string {
+	var elems []string
+
+	path = regxPathKeys.ReplaceAllString(path, "")
+	for _, elem := range strings.Split(strings.Trim(path, "/"), "/") {
+		if i := strings.Index(elem, ":"); i > -1 {
+			elem = elem[i+1:]
+		}
+		elems = append(elems, elem)
+	}
+
+	return strings.Join(elems, "/")
+}
@@ -0,0 +1,158 @@
+//: Copyright Verizon Media
+//: Licensed under the terms of the Apache 2.0 License. See LICENSE file in the project root for terms.
+
+package telemetry
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/yahoo/panoptes-stream/config"
+)
+
+func TestGetExpectedInterval(t *testing.T) {
+	assert.Equal(t, 10*time.Second, getExpectedInterval(&config.Sensor{SampleInterval: 10}))
+	assert.Equal(t, 30*time.Second, getExpectedInterval(&config.Sensor{Mode: "on_change", SampleInterval: 10, HeartbeatInterval: 30}))
+	assert.Equal(t, time.Duration(0), getExpectedInterval(&config.Sensor{Mode: "on_change"}))
+	assert.Equal(t, 20*time.Second, getExpectedInterval(&config.Sensor{ListMode: "poll", PollInterval: 20}))
+	assert.Equal(t, defaultPollInterval*time.Second, getExpectedInterval(&config.Sensor{ListMode: "once"}))
+}
+
+func TestNormalizeSensorPath(t *testing.T) {
+	assert.Equal(t, "interfaces/interface/state", normalizeSensorPath("/openconfig-interfaces:interfaces/interface[name=eth0]/state/"))
+	assert.Equal(t, "", normalizeSensorPath(""))
+}
+
+func TestWatchedSensorMatch(t *testing.T) {
+	ws := &watchedSensor{path: normalizeSensorPath("/interfaces/interface"), subscription: "sub1"}
+
+	assert.True(t, ws.match("interfaces/interface/state/counters", nil))
+	assert.True(t, ws.match("interfaces", nil))
+	assert.False(t, ws.match("interfaces-x", nil))
+	assert.False(t, ws.match("components/component", nil))
+	assert.True(t, ws.match("", map[string]string{"subscriptionId": "sub1"}))
+}
+
+func TestNewWatchdogDisabled(t *testing.T) {
+	cfg := &config.MockConfig{MGlobal: &config.Global{}}
+	sensors := []*config.Sensor{{Path: "/interfaces/interface", SampleInterval: 10}}
+
+	assert.Nil(t, newWatchdog(cfg, "core1.lax", "arista.gnmi", sensors))
+
+	cfg.Global().Watchdog.Enabled = true
+	assert.Nil(t, newWatchdog(cfg, "core1.lax", "arista.gnmi", []*config.Sensor{{Path: "/interfaces", Mode: "on_change"}}))
+}
+
+func TestWatchdogCheck(t *testing.T) {
+	cfg := &config.MockConfig{MGlobal: &config.Global{}}
+	cfg.Global().Watchdog.Enabled = true
+	cfg.Global().Watchdog.MissedIntervals = 3
+
+	sensors := []*config.Sensor{
+		{Path: "/interfaces/interface", SampleInterval: 10},
+		{Path: "/components/component", SampleInterval: 60},
+	}
+
+	w := newWatchdog(cfg, "core1.lax", "arista.gnmi", sensors)
+	if !assert.NotNil(t, w) {
+		return
+	}
+	w.logger = zap.NewNop()
+	defer w.stop()
+
+	assert.Equal(t, 10*time.Second, w.interval)
+
+	now := time.Now()
+	for _, ws := range w.sensors {
+		atomic.StoreInt64(&ws.last, now.UnixNano())
+	}
+
+	assert.False(t, w.check(now.Add(5*time.Second)))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&w.sensors[0].stale))
+
+	// first sensor missed two intervals
+	assert.False(t, w.check(now.Add(25*time.Second)))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&w.sensors[0].stale))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&w.sensors[1].stale))
+	assert.Equal(t, uint64(1), w.sensors[0].metrics["staleTotal"].Get())
+	assert.Equal(t, uint64(2), w.sensors[0].metrics["missedIntervals"].Get())
+
+	// updated by the device
+	w.touch(ExtDataStore{DS: map[string]interface{}{"prefix": "/interfaces/interface/state/counters"}})
+	assert.False(t, w.check(time.Now()))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&w.sensors[0].stale))
+
+	// resubscribe
+	assert.True(t, w.check(time.Now().Add(35*time.Second)))
+}
+
+func TestWatchdogTouchUnwatched(t *testing.T) {
+	cfg := &config.MockConfig{MGlobal: &config.Global{}}
+	cfg.Global().Watchdog.Enabled = true
+
+	sensors := []*config.Sensor{
+		{Path: "/interfaces/interface", SampleInterval: 10},
+		{Path: "/system/state", Mode: "on_change"},
+	}
+
+	w := newWatchdog(cfg, "core1.lax", "arista.gnmi", sensors)
+	if !assert.NotNil(t, w) {
+		return
+	}
+	w.logger = zap.NewNop()
+	defer w.stop()
+
+	assert.Len(t, w.sensors, 1)
+
+	// the on_change sensor data doesn't update the watched sensor
+	atomic.StoreInt64(&w.sensors[0].last, 0)
+	w.touch(ExtDataStore{DS: map[string]interface{}{"prefix": "/system/state"}})
+	assert.Equal(t, int64(0), atomic.LoadInt64(&w.sensors[0].last))
+
+	w.touch(ExtDataStore{DS: map[string]interface{}{"prefix": "/interfaces/interface/state"}})
+	assert.NotEqual(t, int64(0), atomic.LoadInt64(&w.sensors[0].last))
+}
+
+func TestWatchdogRun(t *testing.T) {
+	cfg := &config.MockConfig{MGlobal: &config.Global{}}
+	cfg.Global().Watchdog.Enabled = true
+
+	w := newWatchdog(cfg, "core1.lax", "arista.gnmi", []*config.Sensor{{Path: "/interfaces/interface", SampleInterval: 10}})
+	if !assert.NotNil(t, w) {
+		return
+	}
+	w.logger = zap.NewNop()
+	defer w.stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	outChan := make(ExtDSChan, 1)
+	ch := w.run(ctx, cancel, outChan)
+
+	atomic.StoreInt64(&w.sensors[0].last, 0)
+	ch <- ExtDataStore{DS: map[string]interface{}{"prefix": "/interfaces/interface", "key": "state/mtu"}}
+
+	select {
+	case d := <-outChan:
+		assert.Equal(t, "state/mtu", d.DS["key"])
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	assert.NotEqual(t, int64(0), atomic.LoadInt64(&w.sensors[0].last))
+
+	// output channel is full
+	ch <- ExtDataStore{DS: map[string]interface{}{"prefix": "/interfaces/interface", "key": "state/mtu"}}
+	ch <- ExtDataStore{DS: map[string]interface{}{"prefix": "/interfaces/interface", "key": "state/mtu"}}
+
+	assert.Eventually(t, func() bool {
+		return w.metrics["dropsTotal"].Get() == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Len(t, outChan, 1)
+}